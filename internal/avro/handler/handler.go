@@ -0,0 +1,54 @@
+package avro
+
+import (
+	avromodels "konverter/internal/avro/models"
+	"konverter/internal/avro/usecase"
+	"konverter/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Decode handles Avro binary to JSON decoding requests
+func Decode(c *fiber.Ctx) error {
+	req := avromodels.DecodeRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	res, err := usecase.Decode(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
+
+// DecodeContainer handles Avro Object Container File decoding requests
+func DecodeContainer(c *fiber.Ctx) error {
+	req := avromodels.DecodeContainerRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	res, err := usecase.DecodeContainer(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
+
+// Encode handles JSON to Avro encoding requests
+func Encode(c *fiber.Ctx) error {
+	req := avromodels.EncodeRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	res, err := usecase.Encode(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	MaxSchemaSize = 1 * 1024 * 1024 // 1MB in bytes
+)
+
+type DecodeRequest struct {
+	// Type is the encoding of Data: "base64" or "hex"
+	Type string `json:"type"`
+	// Data is the Avro binary-encoded datum (or data for multiple concatenated records)
+	Data string `json:"data"`
+	// Schema is the Avro schema as JSON
+	Schema string `json:"schema"`
+	// Confluent strips the Confluent Schema Registry wire header (magic byte + 4-byte schema id)
+	Confluent bool `json:"confluent,omitempty"`
+	// StandardJSON outputs unions as plain values instead of Avro JSON encoding ({"string": "x"})
+	StandardJSON bool `json:"standard_json,omitempty"`
+}
+
+func (r *DecodeRequest) Validate() error {
+	if r.Type != "base64" && r.Type != "hex" {
+		return errors.New("type must be either 'base64' or 'hex'")
+	}
+	if strings.TrimSpace(r.Data) == "" {
+		return errors.New("data is required")
+	}
+	if r.Schema == "" {
+		return errors.New("schema is required")
+	}
+	if len(r.Schema) > MaxSchemaSize {
+		return errors.New("schema size exceeds maximum limit of 1MB")
+	}
+	return nil
+}
+
+type DecodeResponse struct {
+	// SchemaID is the Confluent Schema Registry id (only when confluent is set)
+	SchemaID *uint32 `json:"schema_id,omitempty"`
+	// Records are the decoded records in JSON form
+	Records []any `json:"records"`
+}
+
+type DecodeContainerRequest struct {
+	// Type is the encoding of Data: "base64" or "hex"
+	Type string `json:"type"`
+	// Data is the Avro Object Container File content
+	Data string `json:"data"`
+	// StandardJSON outputs unions as plain values instead of Avro JSON encoding ({"string": "x"})
+	StandardJSON bool `json:"standard_json,omitempty"`
+	// Limit is the maximum number of records to return (0 means all)
+	Limit int `json:"limit,omitempty"`
+}
+
+func (r *DecodeContainerRequest) Validate() error {
+	if r.Type != "base64" && r.Type != "hex" {
+		return errors.New("type must be either 'base64' or 'hex'")
+	}
+	if strings.TrimSpace(r.Data) == "" {
+		return errors.New("data is required")
+	}
+	if r.Limit < 0 {
+		return errors.New("limit cannot be negative")
+	}
+	return nil
+}
+
+type DecodeContainerResponse struct {
+	// Codec is the compression codec declared in the header (e.g., "null", "deflate", "snappy")
+	Codec string `json:"codec"`
+	// Schema is the writer schema embedded in the header
+	Schema any `json:"schema"`
+	// Metadata holds the remaining header metadata entries
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// SyncMarker is the 16-byte sync marker as hex
+	SyncMarker string `json:"sync_marker"`
+	// Blocks describes every data block in the file
+	Blocks []ContainerBlock `json:"blocks"`
+	// TotalRecords is the number of records across all blocks
+	TotalRecords int64 `json:"total_records"`
+	// Records are the decoded records in JSON form (up to limit)
+	Records []any `json:"records"`
+	// Truncated is set when more records exist than were returned
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ContainerBlock describes a single data block of an Object Container File
+type ContainerBlock struct {
+	// Offset is the byte offset of the block in the file
+	Offset int `json:"offset"`
+	// Count is the number of records in the block
+	Count int64 `json:"count"`
+	// Size is the serialized (possibly compressed) size of the block in bytes
+	Size int64 `json:"size"`
+	// SyncValid reports whether the block ends with the header sync marker
+	SyncValid bool `json:"sync_valid"`
+}
+
+type EncodeRequest struct {
+	// Type is the output encoding: "base64" or "hex"
+	Type string `json:"type"`
+	// Data is the JSON record, or an array of records when container is set
+	Data string `json:"data"`
+	// Schema is the Avro schema as JSON
+	Schema string `json:"schema"`
+	// Container wraps the records in an Object Container File
+	Container bool `json:"container,omitempty"`
+	// Codec is the container compression codec: "null" (default), "deflate" or "snappy"
+	Codec string `json:"codec,omitempty"`
+	// StandardJSON accepts unions as plain values instead of Avro JSON encoding ({"string": "x"})
+	StandardJSON bool `json:"standard_json,omitempty"`
+}
+
+func (r *EncodeRequest) Validate() error {
+	if r.Type != "base64" && r.Type != "hex" {
+		return errors.New("type must be either 'base64' or 'hex'")
+	}
+	if r.Data == "" {
+		return errors.New("data is required")
+	}
+	if r.Schema == "" {
+		return errors.New("schema is required")
+	}
+	if len(r.Schema) > MaxSchemaSize {
+		return errors.New("schema size exceeds maximum limit of 1MB")
+	}
+	if r.Codec != "" && !r.Container {
+		return errors.New("codec is only supported when container is set")
+	}
+	switch r.Codec {
+	case "", "null", "deflate", "snappy":
+	default:
+		return errors.New("codec must be one of 'null', 'deflate' or 'snappy'")
+	}
+	return nil
+}
+
+type EncodeResponse struct {
+	// Encoded is the Avro payload in the requested encoding
+	Encoded string `json:"encoded"`
+	// Type is the output encoding
+	Type string `json:"type"`
+	// Records is the number of records encoded
+	Records int `json:"records"`
+}
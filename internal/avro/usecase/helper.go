@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"strconv"
+
+	compressmodels "konverter/internal/compress/models"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/linkedin/goavro/v2"
+)
+
+const (
+	// containerMagic is the 4-byte magic that starts every Object Container File
+	containerMagic = "Obj\x01"
+	// syncMarkerSize is the size of the container sync marker in bytes
+	syncMarkerSize = 16
+	// confluentHeaderSize is the magic byte plus the 4-byte schema id
+	confluentHeaderSize = 5
+)
+
+// newCodec builds a codec using either Avro JSON encoding or standard JSON for unions
+func newCodec(schema string, standardJSON bool) (*goavro.Codec, error) {
+	var codec *goavro.Codec
+	var err error
+	if standardJSON {
+		codec, err = goavro.NewCodecForStandardJSONFull(schema)
+	} else {
+		codec, err = goavro.NewCodec(schema)
+	}
+	if err != nil {
+		return nil, errors.New("invalid schema: " + err.Error())
+	}
+	return codec, nil
+}
+
+// containerHeader holds the parsed header of an Object Container File
+type containerHeader struct {
+	metadata   map[string][]byte
+	syncMarker []byte
+	// size is the header length in bytes, i.e. the offset of the first block
+	size int
+}
+
+// parseContainerHeader reads the magic, metadata map and sync marker of an Object Container File
+func parseContainerHeader(data []byte) (containerHeader, error) {
+	if !bytes.HasPrefix(data, []byte(containerMagic)) {
+		return containerHeader{}, errors.New("not an Avro object container file: missing 'Obj\\x01' magic")
+	}
+
+	header := containerHeader{metadata: map[string][]byte{}}
+	offset := len(containerMagic)
+
+	// The metadata map is a series of blocks terminated by a zero count
+	for {
+		count, n := binary.Varint(data[offset:])
+		if n <= 0 {
+			return containerHeader{}, errors.New("invalid container header: malformed metadata block count")
+		}
+		offset += n
+		if count == 0 {
+			break
+		}
+		if count < 0 {
+			// Negative counts are followed by the block size in bytes, which we don't need
+			count = -count
+			_, n = binary.Varint(data[offset:])
+			if n <= 0 {
+				return containerHeader{}, errors.New("invalid container header: malformed metadata block size")
+			}
+			offset += n
+		}
+		for i := int64(0); i < count; i++ {
+			key, n, err := readBytes(data[offset:])
+			if err != nil {
+				return containerHeader{}, errors.New("invalid container header: " + err.Error())
+			}
+			offset += n
+			value, n, err := readBytes(data[offset:])
+			if err != nil {
+				return containerHeader{}, errors.New("invalid container header: " + err.Error())
+			}
+			offset += n
+			header.metadata[string(key)] = value
+		}
+	}
+
+	if len(data[offset:]) < syncMarkerSize {
+		return containerHeader{}, errors.New("invalid container header: missing sync marker")
+	}
+	header.syncMarker = data[offset : offset+syncMarkerSize]
+	header.size = offset + syncMarkerSize
+
+	return header, nil
+}
+
+// walkContainerBlocks lists the data blocks that follow the header, checking each sync marker
+func walkContainerBlocks(data []byte, header containerHeader) ([]containerBlock, error) {
+	blocks := []containerBlock{}
+	offset := header.size
+
+	for offset < len(data) {
+		block := containerBlock{offset: offset}
+
+		count, n := binary.Varint(data[offset:])
+		if n <= 0 {
+			return nil, errors.New("malformed block record count")
+		}
+		offset += n
+		size, n := binary.Varint(data[offset:])
+		if n <= 0 || size < 0 {
+			return nil, errors.New("malformed block size")
+		}
+		offset += n
+		block.data = data[offset:]
+		// Compare without adding to size, which can be near the int64 maximum
+		if size > int64(len(data)-offset-syncMarkerSize) {
+			return nil, errors.New("block extends past end of data")
+		}
+		offset += int(size)
+
+		block.data = block.data[:size]
+		block.count = count
+		block.size = size
+		block.syncValid = bytes.Equal(data[offset:offset+syncMarkerSize], header.syncMarker)
+		offset += syncMarkerSize
+
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
+
+// containerBlock describes a data block found by walkContainerBlocks
+type containerBlock struct {
+	offset    int
+	count     int64
+	size      int64
+	syncValid bool
+	// data is the block's records, compressed with the container codec
+	data []byte
+}
+
+// errContainerTooLarge is returned when the decompressed blocks would exceed compressmodels.MaxDecompressSize
+var errContainerTooLarge = errors.New("decompressed container exceeds " + strconv.Itoa(compressmodels.MaxDecompressSize>>20) + "MB")
+
+// decompressBlock decompresses a block's data with the container codec into at most limit bytes
+// Snappy blocks end with the big-endian CRC-32 of the decompressed data
+func decompressBlock(codecName string, data []byte, limit int) ([]byte, error) {
+	switch codecName {
+	case goavro.CompressionNullLabel:
+		return data, nil
+	case goavro.CompressionDeflateLabel:
+		out, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(data)), int64(limit)+1))
+		if err != nil {
+			return nil, errors.New("failed to decompress deflate block: " + err.Error())
+		}
+		if len(out) > limit {
+			return nil, errContainerTooLarge
+		}
+		return out, nil
+	case goavro.CompressionSnappyLabel:
+		if len(data) < 4 {
+			return nil, errors.New("snappy block is missing its CRC-32")
+		}
+		compressed, checksum := data[:len(data)-4], binary.BigEndian.Uint32(data[len(data)-4:])
+		size, err := snappy.DecodedLen(compressed)
+		if err != nil {
+			return nil, errors.New("failed to decompress snappy block: " + err.Error())
+		}
+		if size > limit {
+			return nil, errContainerTooLarge
+		}
+		out, err := snappy.Decode(nil, compressed)
+		if err != nil {
+			return nil, errors.New("failed to decompress snappy block: " + err.Error())
+		}
+		if crc32.ChecksumIEEE(out) != checksum {
+			return nil, errors.New("snappy block CRC-32 mismatch")
+		}
+		return out, nil
+	default:
+		return nil, errors.New("unsupported container codec: " + codecName)
+	}
+}
+
+// readBytes reads an Avro length-prefixed byte sequence and returns it with the number of bytes consumed
+func readBytes(data []byte) ([]byte, int, error) {
+	length, n := binary.Varint(data)
+	if n <= 0 || length < 0 {
+		return nil, 0, errors.New("malformed length prefix")
+	}
+	if int64(len(data)-n) < length {
+		return nil, 0, errors.New("length exceeds available data")
+	}
+	return data[n : n+int(length)], n + int(length), nil
+}
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	avromodels "konverter/internal/avro/models"
+	compressmodels "konverter/internal/compress/models"
+	encodingusecase "konverter/internal/encoding/usecase"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/linkedin/goavro/v2"
+)
+
+// Decode decodes one or more concatenated Avro binary records using the given schema
+func Decode(req avromodels.DecodeRequest) (avromodels.DecodeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return avromodels.DecodeResponse{}, err
+	}
+
+	data, err := encodingusecase.Decode(req.Type, req.Data)
+	if err != nil {
+		return avromodels.DecodeResponse{}, errors.New("failed to decode " + req.Type + " data: " + err.Error())
+	}
+
+	codec, err := newCodec(req.Schema, req.StandardJSON)
+	if err != nil {
+		return avromodels.DecodeResponse{}, err
+	}
+
+	response := avromodels.DecodeResponse{Records: []any{}}
+
+	// Strip the Confluent wire header: magic byte 0 followed by a big-endian schema id
+	if req.Confluent {
+		if len(data) < confluentHeaderSize || data[0] != 0 {
+			return avromodels.DecodeResponse{}, errors.New("invalid Confluent wire format: expected magic byte 0 and 4-byte schema id")
+		}
+		schemaID := binary.BigEndian.Uint32(data[1:confluentHeaderSize])
+		response.SchemaID = &schemaID
+		data = data[confluentHeaderSize:]
+	}
+
+	for len(data) > 0 {
+		native, rest, err := codec.NativeFromBinary(data)
+		if err != nil {
+			return avromodels.DecodeResponse{}, errors.New("failed to decode record " + strconv.Itoa(len(response.Records)) + ": " + err.Error())
+		}
+		// Guard against zero-length datums (e.g. "null" schema) looping forever
+		if len(rest) == len(data) {
+			return avromodels.DecodeResponse{}, errors.New(strconv.Itoa(len(data)) + " trailing bytes could not be decoded with the schema")
+		}
+		record, err := toJSONValue(codec, native)
+		if err != nil {
+			return avromodels.DecodeResponse{}, err
+		}
+		response.Records = append(response.Records, record)
+		data = rest
+	}
+
+	return response, nil
+}
+
+// DecodeContainer reads an Avro Object Container File, reporting its header, blocks and records
+func DecodeContainer(req avromodels.DecodeContainerRequest) (avromodels.DecodeContainerResponse, error) {
+	if err := req.Validate(); err != nil {
+		return avromodels.DecodeContainerResponse{}, err
+	}
+
+	data, err := encodingusecase.Decode(req.Type, req.Data)
+	if err != nil {
+		return avromodels.DecodeContainerResponse{}, errors.New("failed to decode " + req.Type + " data: " + err.Error())
+	}
+
+	header, err := parseContainerHeader(data)
+	if err != nil {
+		return avromodels.DecodeContainerResponse{}, err
+	}
+
+	blocks, err := walkContainerBlocks(data, header)
+	if err != nil {
+		return avromodels.DecodeContainerResponse{}, errors.New("invalid container data: " + err.Error())
+	}
+
+	schemaText := header.metadata["avro.schema"]
+	var schema any
+	if err := jsoniter.Unmarshal(schemaText, &schema); err != nil {
+		return avromodels.DecodeContainerResponse{}, errors.New("invalid schema in container header: " + err.Error())
+	}
+
+	codecName := string(header.metadata["avro.codec"])
+	if codecName == "" {
+		codecName = goavro.CompressionNullLabel
+	}
+
+	response := avromodels.DecodeContainerResponse{
+		Codec:      codecName,
+		Schema:     schema,
+		SyncMarker: hex.EncodeToString(header.syncMarker),
+		Blocks:     make([]avromodels.ContainerBlock, 0, len(blocks)),
+		Records:    []any{},
+	}
+
+	for key, value := range header.metadata {
+		if key == "avro.schema" || key == "avro.codec" {
+			continue
+		}
+		if response.Metadata == nil {
+			response.Metadata = map[string]string{}
+		}
+		response.Metadata[key] = string(value)
+	}
+
+	for _, block := range blocks {
+		response.Blocks = append(response.Blocks, avromodels.ContainerBlock{
+			Offset:    block.offset,
+			Count:     block.count,
+			Size:      block.size,
+			SyncValid: block.syncValid,
+		})
+		response.TotalRecords += block.count
+	}
+
+	// Decode records with the writer schema, decompressing the blocks within the compress module's limit
+	codec, err := newCodec(string(schemaText), req.StandardJSON)
+	if err != nil {
+		return avromodels.DecodeContainerResponse{}, err
+	}
+
+	budget := compressmodels.MaxDecompressSize
+blocks:
+	for _, block := range blocks {
+		if !block.syncValid {
+			return avromodels.DecodeContainerResponse{}, errors.New("block at offset " + strconv.Itoa(block.offset) + " has an invalid sync marker")
+		}
+		records, err := decompressBlock(codecName, block.data, budget)
+		if err != nil {
+			return avromodels.DecodeContainerResponse{}, err
+		}
+		budget -= len(records)
+
+		for i := int64(0); i < block.count; i++ {
+			if req.Limit > 0 && len(response.Records) >= req.Limit {
+				response.Truncated = true
+				break blocks
+			}
+			native, rest, err := codec.NativeFromBinary(records)
+			if err != nil {
+				return avromodels.DecodeContainerResponse{}, errors.New("failed to decode record " + strconv.Itoa(len(response.Records)) + ": " + err.Error())
+			}
+			// Guard against zero-length datums (e.g. "null" schema) and hostile record counts
+			if len(rest) == len(records) {
+				return avromodels.DecodeContainerResponse{}, errors.New("record " + strconv.Itoa(len(response.Records)) + " decoded from no data")
+			}
+			record, err := toJSONValue(codec, native)
+			if err != nil {
+				return avromodels.DecodeContainerResponse{}, err
+			}
+			response.Records = append(response.Records, record)
+			records = rest
+		}
+	}
+
+	return response, nil
+}
+
+// Encode encodes JSON data to Avro binary, or to an Object Container File when container is set
+func Encode(req avromodels.EncodeRequest) (avromodels.EncodeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return avromodels.EncodeResponse{}, err
+	}
+
+	codec, err := newCodec(req.Schema, req.StandardJSON)
+	if err != nil {
+		return avromodels.EncodeResponse{}, err
+	}
+
+	var records []any
+	if req.Container {
+		// A container holds many records, so the data is a JSON array of them
+		var raw []jsoniter.RawMessage
+		if err := jsoniter.Unmarshal([]byte(req.Data), &raw); err != nil {
+			return avromodels.EncodeResponse{}, errors.New("data must be a JSON array of records when container is set: " + err.Error())
+		}
+		for i, item := range raw {
+			native, _, err := codec.NativeFromTextual(item)
+			if err != nil {
+				return avromodels.EncodeResponse{}, errors.New("invalid record " + strconv.Itoa(i) + ": " + err.Error())
+			}
+			records = append(records, native)
+		}
+	} else {
+		native, _, err := codec.NativeFromTextual([]byte(req.Data))
+		if err != nil {
+			return avromodels.EncodeResponse{}, errors.New("invalid JSON data: " + err.Error())
+		}
+		records = append(records, native)
+	}
+
+	var encodedBytes []byte
+	if req.Container {
+		compression := req.Codec
+		if compression == "" {
+			compression = goavro.CompressionNullLabel
+		}
+		var buf bytes.Buffer
+		writer, err := goavro.NewOCFWriter(goavro.OCFConfig{
+			W:               &buf,
+			Codec:           codec,
+			CompressionName: compression,
+		})
+		if err != nil {
+			return avromodels.EncodeResponse{}, errors.New("failed to create container: " + err.Error())
+		}
+		if err := writer.Append(records); err != nil {
+			return avromodels.EncodeResponse{}, errors.New("failed to encode avro: " + err.Error())
+		}
+		encodedBytes = buf.Bytes()
+	} else {
+		encodedBytes, err = codec.BinaryFromNative(nil, records[0])
+		if err != nil {
+			return avromodels.EncodeResponse{}, errors.New("failed to encode avro: " + err.Error())
+		}
+	}
+
+	encoded, err := encodingusecase.Encode(req.Type, encodedBytes)
+	if err != nil {
+		return avromodels.EncodeResponse{}, err
+	}
+
+	return avromodels.EncodeResponse{
+		Encoded: encoded,
+		Type:    req.Type,
+		Records: len(records),
+	}, nil
+}
+
+// toJSONValue converts a native Avro datum to a JSON-compatible value
+func toJSONValue(codec *goavro.Codec, native any) (any, error) {
+	textual, err := codec.TextualFromNative(nil, native)
+	if err != nil {
+		return nil, errors.New("failed to convert avro to JSON: " + err.Error())
+	}
+	var value any
+	if err := jsoniter.Unmarshal(textual, &value); err != nil {
+		return nil, errors.New("failed to convert avro to JSON: " + err.Error())
+	}
+	return value, nil
+}
@@ -0,0 +1,82 @@
+package compress
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	compressmodels "konverter/internal/compress/models"
+	"konverter/internal/compress/usecase"
+	"konverter/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Compress handles compression requests from JSON bodies or multipart file uploads
+func Compress(c *fiber.Ctx) error {
+	req := compressmodels.CompressRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	upload, err := readUpload(c)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+	req.Upload = upload
+
+	res, err := usecase.Compress(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
+
+// Decompress handles decompression requests from JSON bodies or multipart file uploads
+func Decompress(c *fiber.Ctx) error {
+	req := compressmodels.DecompressRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	upload, err := readUpload(c)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+	req.Upload = upload
+
+	res, err := usecase.Decompress(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
+
+// readUpload returns the content of the multipart "file" field, or nil when there is none
+func readUpload(c *fiber.Ctx) ([]byte, error) {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		return nil, nil
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	files := form.File["file"]
+	if len(files) == 0 {
+		return nil, nil
+	}
+	if files[0].Size > compressmodels.MaxDataSize {
+		return nil, errors.New("file size exceeds maximum limit of 10MB")
+	}
+
+	file, err := files[0].Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
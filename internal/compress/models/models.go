@@ -0,0 +1,123 @@
+package models
+
+import (
+	"errors"
+	"slices"
+)
+
+const (
+	MaxDataSize       = 10 * 1024 * 1024  // 10MB in bytes
+	MaxDecompressSize = 100 * 1024 * 1024 // 100MB in bytes, guards against decompression bombs
+)
+
+// Algorithms lists the supported compression algorithms
+var Algorithms = []string{"gzip", "zlib", "deflate", "zstd", "brotli", "snappy", "snappy-framed", "lz4"}
+
+type CompressRequest struct {
+	// Algorithm is one of Algorithms
+	Algorithm string `json:"algorithm" form:"algorithm"`
+	// Type is the encoding of Data: "base64", "hex" or "raw" (ignored for file uploads)
+	Type string `json:"type" form:"type"`
+	// Data is the input to compress
+	Data string `json:"data" form:"data"`
+	// Level is the optional compression level; the valid range depends on the algorithm
+	Level *int `json:"level,omitempty" form:"level"`
+	// OutputType is the encoding of the output: "base64" (default) or "hex"
+	OutputType string `json:"output_type,omitempty" form:"output_type"`
+	// Upload holds the content of an uploaded file, which takes precedence over Data
+	Upload []byte `json:"-" form:"-"`
+}
+
+func (r *CompressRequest) Validate() error {
+	if !isAlgorithm(r.Algorithm) {
+		return errors.New("algorithm must be one of 'gzip', 'zlib', 'deflate', 'zstd', 'brotli', 'snappy', 'snappy-framed' or 'lz4'")
+	}
+	if err := validateInput(r.Type, r.Data, r.Upload); err != nil {
+		return err
+	}
+	if r.OutputType != "" && r.OutputType != "base64" && r.OutputType != "hex" {
+		return errors.New("output_type must be either 'base64' or 'hex'")
+	}
+	return nil
+}
+
+type DecompressRequest struct {
+	// Algorithm is optional; when empty it is detected from magic bytes
+	Algorithm string `json:"algorithm,omitempty" form:"algorithm"`
+	// Type is the encoding of Data: "base64", "hex" or "raw" (ignored for file uploads)
+	Type string `json:"type" form:"type"`
+	// Data is the compressed input
+	Data string `json:"data" form:"data"`
+	// OutputType is the encoding of the output: "text", "base64" or "hex"; defaults to text when valid UTF-8
+	OutputType string `json:"output_type,omitempty" form:"output_type"`
+	// Upload holds the content of an uploaded file, which takes precedence over Data
+	Upload []byte `json:"-" form:"-"`
+}
+
+func (r *DecompressRequest) Validate() error {
+	if r.Algorithm != "" && !isAlgorithm(r.Algorithm) {
+		return errors.New("algorithm must be one of 'gzip', 'zlib', 'deflate', 'zstd', 'brotli', 'snappy', 'snappy-framed' or 'lz4'")
+	}
+	if err := validateInput(r.Type, r.Data, r.Upload); err != nil {
+		return err
+	}
+	if r.OutputType != "" && r.OutputType != "text" && r.OutputType != "base64" && r.OutputType != "hex" {
+		return errors.New("output_type must be one of 'text', 'base64' or 'hex'")
+	}
+	return nil
+}
+
+type CompressResponse struct {
+	// Algorithm is the algorithm used
+	Algorithm string `json:"algorithm"`
+	// Output is the compressed data in OutputType encoding
+	Output string `json:"output"`
+	// OutputType is the encoding of Output
+	OutputType string `json:"output_type"`
+	// OriginalSize is the uncompressed size in bytes
+	OriginalSize int `json:"original_size"`
+	// CompressedSize is the compressed size in bytes
+	CompressedSize int `json:"compressed_size"`
+	// Ratio is compressed size divided by original size (lower is better)
+	Ratio float64 `json:"ratio"`
+}
+
+type DecompressResponse struct {
+	// Algorithm is the algorithm used
+	Algorithm string `json:"algorithm"`
+	// Detection is how the algorithm was chosen: "specified", "magic" or "trial"
+	Detection string `json:"detection"`
+	// Output is the decompressed data in OutputType encoding
+	Output string `json:"output"`
+	// OutputType is the encoding of Output
+	OutputType string `json:"output_type"`
+	// OriginalSize is the decompressed size in bytes
+	OriginalSize int `json:"original_size"`
+	// CompressedSize is the compressed size in bytes
+	CompressedSize int `json:"compressed_size"`
+	// Ratio is compressed size divided by original size (lower is better)
+	Ratio float64 `json:"ratio"`
+}
+
+func isAlgorithm(algorithm string) bool {
+	return slices.Contains(Algorithms, algorithm)
+}
+
+func validateInput(dataType, data string, upload []byte) error {
+	if upload != nil {
+		if len(upload) > MaxDataSize {
+			return errors.New("file size exceeds maximum limit of 10MB")
+		}
+		return nil
+	}
+	if dataType != "base64" && dataType != "hex" && dataType != "raw" {
+		return errors.New("type must be one of 'base64', 'hex' or 'raw'")
+	}
+	if data == "" {
+		return errors.New("data is required")
+	}
+	if len(data) > MaxDataSize {
+		return errors.New("data size exceeds maximum limit of 10MB")
+	}
+	return nil
+}
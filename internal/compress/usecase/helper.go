@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+
+	compressmodels "konverter/internal/compress/models"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/flate"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+var (
+	gzipMagic         = []byte{0x1F, 0x8B}
+	zstdMagic         = []byte{0x28, 0xB5, 0x2F, 0xFD}
+	lz4Magic          = []byte{0x04, 0x22, 0x4D, 0x18}
+	snappyFramedMagic = []byte("\xff\x06\x00\x00sNaPpY")
+)
+
+var errOutputTooLarge = errors.New("decompressed size exceeds maximum limit of 100MB")
+
+// inputCodec returns the shared encoding codec for a request type; "raw" data is taken as-is, like "text"
+func inputCodec(dataType string) string {
+	if dataType == "raw" {
+		return "text"
+	}
+	return dataType
+}
+
+// compressData compresses data with the given algorithm and optional level
+func compressData(algorithm string, data []byte, level *int) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	var err error
+
+	switch algorithm {
+	case "gzip":
+		w, err = gzip.NewWriterLevel(&buf, levelOr(level, gzip.DefaultCompression))
+	case "zlib":
+		w, err = zlib.NewWriterLevel(&buf, levelOr(level, zlib.DefaultCompression))
+	case "deflate":
+		w, err = flate.NewWriter(&buf, levelOr(level, flate.DefaultCompression))
+	case "zstd":
+		w, err = zstd.NewWriter(&buf, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(levelOr(level, 3))))
+	case "brotli":
+		lvl := levelOr(level, brotli.DefaultCompression)
+		if lvl < brotli.BestSpeed || lvl > brotli.BestCompression {
+			return nil, errors.New("brotli level must be between 0 and 11")
+		}
+		w = brotli.NewWriterLevel(&buf, lvl)
+	case "snappy":
+		if level != nil {
+			return nil, errors.New("snappy does not support compression levels")
+		}
+		return snappy.Encode(nil, data), nil
+	case "snappy-framed":
+		if level != nil {
+			return nil, errors.New("snappy does not support compression levels")
+		}
+		w = snappy.NewBufferedWriter(&buf)
+	case "lz4":
+		lw := lz4.NewWriter(&buf)
+		if level != nil {
+			lvl, err := lz4Level(*level)
+			if err != nil {
+				return nil, err
+			}
+			if err := lw.Apply(lz4.CompressionLevelOption(lvl)); err != nil {
+				return nil, err
+			}
+		}
+		w = lw
+	default:
+		return nil, errors.New("unsupported algorithm: " + algorithm)
+	}
+	if err != nil {
+		return nil, errors.New("invalid " + algorithm + " level: " + err.Error())
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressData decompresses data with the given algorithm, capping the output size
+func decompressData(algorithm string, data []byte) ([]byte, error) {
+	var r io.Reader
+
+	switch algorithm {
+	case "gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		r = gr
+	case "zlib":
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	case "deflate":
+		fr := flate.NewReader(bytes.NewReader(data))
+		defer fr.Close()
+		r = fr
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(data), zstd.WithDecoderMaxMemory(compressmodels.MaxDecompressSize))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	case "brotli":
+		r = brotli.NewReader(bytes.NewReader(data))
+	case "snappy":
+		size, err := snappy.DecodedLen(data)
+		if err != nil {
+			return nil, err
+		}
+		if size > compressmodels.MaxDecompressSize {
+			return nil, errOutputTooLarge
+		}
+		return snappy.Decode(nil, data)
+	case "snappy-framed":
+		r = snappy.NewReader(bytes.NewReader(data))
+	case "lz4":
+		r = lz4.NewReader(bytes.NewReader(data))
+	default:
+		return nil, errors.New("unsupported algorithm: " + algorithm)
+	}
+
+	// Read one byte past the limit to tell "exactly at limit" from "too large"
+	out, err := io.ReadAll(io.LimitReader(r, compressmodels.MaxDecompressSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(out) > compressmodels.MaxDecompressSize {
+		return nil, errOutputTooLarge
+	}
+	return out, nil
+}
+
+// detectAlgorithm identifies the compression format from its magic bytes
+func detectAlgorithm(data []byte) (string, bool) {
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		return "gzip", true
+	case bytes.HasPrefix(data, zstdMagic):
+		return "zstd", true
+	case bytes.HasPrefix(data, lz4Magic):
+		return "lz4", true
+	case bytes.HasPrefix(data, snappyFramedMagic):
+		return "snappy-framed", true
+	case len(data) >= 2 && data[0]&0x0F == 8 && data[0]>>4 <= 7 && (uint16(data[0])<<8|uint16(data[1]))%31 == 0:
+		return "zlib", true
+	}
+	return "", false
+}
+
+// trialAlgorithms are formats without magic bytes, tried in order of how strictly they validate input
+var trialAlgorithms = []string{"snappy", "brotli", "deflate"}
+
+// levelOr returns the requested level or the algorithm default
+func levelOr(level *int, defaultLevel int) int {
+	if level == nil {
+		return defaultLevel
+	}
+	return *level
+}
+
+// lz4Level maps levels 0-9 to lz4 compression levels, 0 being the fast default
+func lz4Level(level int) (lz4.CompressionLevel, error) {
+	levels := []lz4.CompressionLevel{lz4.Fast, lz4.Level1, lz4.Level2, lz4.Level3, lz4.Level4, lz4.Level5, lz4.Level6, lz4.Level7, lz4.Level8, lz4.Level9}
+	if level < 0 || level >= len(levels) {
+		return 0, errors.New("lz4 level must be between 0 and 9, got " + strconv.Itoa(level))
+	}
+	return levels[level], nil
+}
+
+// compressionRatio is the compressed size divided by the original size
+func compressionRatio(compressed, original int) float64 {
+	if original == 0 {
+		return 0
+	}
+	return float64(int(float64(compressed)/float64(original)*10000+0.5)) / 10000
+}
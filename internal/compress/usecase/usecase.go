@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	compressmodels "konverter/internal/compress/models"
+	encodingusecase "konverter/internal/encoding/usecase"
+)
+
+// Compress compresses the input with the requested algorithm
+func Compress(req compressmodels.CompressRequest) (compressmodels.CompressResponse, error) {
+	if err := req.Validate(); err != nil {
+		return compressmodels.CompressResponse{}, err
+	}
+
+	data := req.Upload
+	if data == nil {
+		var err error
+		data, err = encodingusecase.Decode(inputCodec(req.Type), req.Data)
+		if err != nil {
+			return compressmodels.CompressResponse{}, errors.New("failed to decode " + req.Type + " data: " + err.Error())
+		}
+	}
+
+	compressed, err := compressData(req.Algorithm, data, req.Level)
+	if err != nil {
+		return compressmodels.CompressResponse{}, errors.New("failed to compress: " + err.Error())
+	}
+
+	outputType := req.OutputType
+	if outputType == "" {
+		outputType = "base64"
+	}
+	output, err := encodingusecase.Encode(outputType, compressed)
+	if err != nil {
+		return compressmodels.CompressResponse{}, err
+	}
+
+	return compressmodels.CompressResponse{
+		Algorithm:      req.Algorithm,
+		Output:         output,
+		OutputType:     outputType,
+		OriginalSize:   len(data),
+		CompressedSize: len(compressed),
+		Ratio:          compressionRatio(len(compressed), len(data)),
+	}, nil
+}
+
+// Decompress decompresses the input, detecting the algorithm from magic bytes when not specified
+func Decompress(req compressmodels.DecompressRequest) (compressmodels.DecompressResponse, error) {
+	if err := req.Validate(); err != nil {
+		return compressmodels.DecompressResponse{}, err
+	}
+
+	data := req.Upload
+	if data == nil {
+		var err error
+		data, err = encodingusecase.Decode(inputCodec(req.Type), req.Data)
+		if err != nil {
+			return compressmodels.DecompressResponse{}, errors.New("failed to decode " + req.Type + " data: " + err.Error())
+		}
+	}
+
+	algorithm := req.Algorithm
+	detection := "specified"
+	var decompressed []byte
+	var err error
+
+	if algorithm == "" {
+		if detected, ok := detectAlgorithm(data); ok {
+			algorithm, detection = detected, "magic"
+		}
+	}
+
+	if algorithm != "" {
+		decompressed, err = decompressData(algorithm, data)
+		if err != nil {
+			return compressmodels.DecompressResponse{}, errors.New("failed to decompress " + algorithm + ": " + err.Error())
+		}
+	} else {
+		// Formats without magic bytes can only be identified by decoding successfully
+		for _, candidate := range trialAlgorithms {
+			decompressed, err = decompressData(candidate, data)
+			if err == nil {
+				algorithm, detection = candidate, "trial"
+				break
+			}
+			if errors.Is(err, errOutputTooLarge) {
+				return compressmodels.DecompressResponse{}, err
+			}
+		}
+		if algorithm == "" {
+			return compressmodels.DecompressResponse{}, errors.New("unable to detect compression format, please specify algorithm")
+		}
+	}
+
+	outputType := req.OutputType
+	if outputType == "" {
+		outputType = "base64"
+		if utf8.Valid(decompressed) {
+			outputType = "text"
+		}
+	}
+	if outputType == "text" && !utf8.Valid(decompressed) {
+		return compressmodels.DecompressResponse{}, errors.New("decompressed data is not valid UTF-8 text, use output_type 'base64' or 'hex'")
+	}
+	output, err := encodingusecase.Encode(outputType, decompressed)
+	if err != nil {
+		return compressmodels.DecompressResponse{}, err
+	}
+
+	return compressmodels.DecompressResponse{
+		Algorithm:      algorithm,
+		Detection:      detection,
+		Output:         output,
+		OutputType:     outputType,
+		OriginalSize:   len(decompressed),
+		CompressedSize: len(data),
+		Ratio:          compressionRatio(len(data), len(decompressed)),
+	}, nil
+}
@@ -0,0 +1,54 @@
+package cron
+
+import (
+	cronmodels "konverter/internal/cron/models"
+	"konverter/internal/cron/usecase"
+	"konverter/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Explain handles cron expression description requests
+func Explain(c *fiber.Ctx) error {
+	req := cronmodels.ExplainRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	res, err := usecase.Explain(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
+
+// Validate handles cron expression validation requests
+func Validate(c *fiber.Ctx) error {
+	req := cronmodels.ExplainRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	res, err := usecase.Validate(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
+
+// Schedule handles next and previous fire time requests
+func Schedule(c *fiber.Ctx) error {
+	req := cronmodels.ScheduleRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	res, err := usecase.Schedule(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
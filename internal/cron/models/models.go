@@ -0,0 +1,124 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	DefaultRunCount = 5
+	MaxRunCount     = 100
+)
+
+// Dialects lists the supported cron syntaxes
+var Dialects = []string{"standard", "seconds", "quartz", "aws"}
+
+type ExplainRequest struct {
+	// Expression is the cron expression (e.g., "*/5 * * * *", "0 0 12 ? * MON-FRI", "cron(0 8 ? * 2#1 *)", "@daily")
+	Expression string `json:"expression"`
+	// Dialect is optional: "standard" (5 fields), "seconds" (6 fields), "quartz" (6-7 fields) or "aws" (EventBridge);
+	// detected from the expression when empty
+	Dialect string `json:"dialect,omitempty"`
+}
+
+func (r *ExplainRequest) Validate() error {
+	if strings.TrimSpace(r.Expression) == "" {
+		return errors.New("expression is required")
+	}
+	return validateDialect(r.Dialect)
+}
+
+type ScheduleRequest struct {
+	// Expression is the cron expression
+	Expression string `json:"expression"`
+	// Dialect is optional; see ExplainRequest
+	Dialect string `json:"dialect,omitempty"`
+	// Timezone is the timezone the schedule runs in (e.g., "America/New_York"); defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
+	// From is optional start of the search as RFC3339 or Unix seconds; defaults to now
+	From string `json:"from,omitempty"`
+	// Count is the number of fire times to list; defaults to DefaultRunCount, at most MaxRunCount
+	Count int `json:"count,omitempty"`
+	// Direction is "next" (default) for fire times after From or "previous" for fire times before it
+	Direction string `json:"direction,omitempty"`
+}
+
+func (r *ScheduleRequest) Validate() error {
+	if strings.TrimSpace(r.Expression) == "" {
+		return errors.New("expression is required")
+	}
+	if r.Count < 0 || r.Count > MaxRunCount {
+		return errors.New("count must be between 1 and 100")
+	}
+	if r.Direction != "" && r.Direction != "next" && r.Direction != "previous" {
+		return errors.New("direction must be either 'next' or 'previous'")
+	}
+	return validateDialect(r.Dialect)
+}
+
+func validateDialect(dialect string) error {
+	switch dialect {
+	case "", "standard", "seconds", "quartz", "aws":
+		return nil
+	}
+	return errors.New("dialect must be one of 'standard', 'seconds', 'quartz' or 'aws'")
+}
+
+// Field is one parsed field of a cron expression
+type Field struct {
+	// Name is the field name (e.g., "minute", "day_of_week")
+	Name string `json:"name"`
+	// Value is the field as written
+	Value string `json:"value"`
+	// Description is the field in English
+	Description string `json:"description"`
+}
+
+type ExplainResponse struct {
+	// Expression is the original expression
+	Expression string `json:"expression"`
+	// Dialect is the syntax the expression was parsed as
+	Dialect string `json:"dialect"`
+	// Normalized is the expression with macros expanded and the AWS cron(...) wrapper removed
+	Normalized string `json:"normalized"`
+	// Fields are the parsed fields in order
+	Fields []Field `json:"fields"`
+	// Description is the schedule in English
+	Description string `json:"description"`
+}
+
+type ValidateResponse struct {
+	// Valid is set when the expression parses in Dialect
+	Valid bool `json:"valid"`
+	// Dialect is the syntax the expression was checked against
+	Dialect string `json:"dialect,omitempty"`
+	// Error explains why the expression is invalid
+	Error string `json:"error,omitempty"`
+}
+
+// Run is one fire time of a schedule
+type Run struct {
+	// Time is the fire time in RFC3339 format in the schedule's timezone
+	Time string `json:"time"`
+	// GMT is the fire time in RFC3339 format in GMT/UTC
+	GMT string `json:"gmt"`
+	// Seconds is the fire time as a Unix timestamp
+	Seconds int64 `json:"seconds"`
+	// DSTNote explains how a DST change affected this run, if it did
+	DSTNote string `json:"dst_note,omitempty"`
+}
+
+type ScheduleResponse struct {
+	// Expression is the original expression
+	Expression string `json:"expression"`
+	// Dialect is the syntax the expression was parsed as
+	Dialect string `json:"dialect"`
+	// Description is the schedule in English
+	Description string `json:"description"`
+	// Timezone is the timezone the runs are computed in
+	Timezone string `json:"timezone"`
+	// Direction is "next" or "previous"
+	Direction string `json:"direction"`
+	// Runs are the fire times, nearest to From first; fewer than Count when the schedule ends or never fires
+	Runs []Run `json:"runs"`
+}
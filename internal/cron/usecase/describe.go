@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ordinals = []string{"", "first", "second", "third", "fourth", "fifth"}
+
+// joinWords joins phrases as "a", "a and b" or "a, b and c"
+func joinWords(words []string) string {
+	switch len(words) {
+	case 0:
+		return ""
+	case 1:
+		return words[0]
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
+
+// plural appends "s" to the first word of unit unless n is 1, e.g. "days of the week"
+func plural(n int, unit string) string {
+	if n == 1 {
+		return unit
+	}
+	head, rest, ok := strings.Cut(unit, " ")
+	if !ok {
+		return unit + "s"
+	}
+	return head + "s " + rest
+}
+
+// valueName renders a field value: month and weekday names, zero-padded hours, plain numbers otherwise
+func (s *schedule) valueName(kind fieldKind, v int) string {
+	switch kind.name {
+	case "month":
+		return time.Month(v).String()
+	case "day_of_week":
+		if s.dialect.quartzDays {
+			return time.Weekday(v - 1).String()
+		}
+		return time.Weekday(v % 7).String()
+	case "hour":
+		return fmt.Sprintf("%02d:00", v)
+	}
+	return strconv.Itoa(v)
+}
+
+// itemPhrases renders each plain item of a field, e.g. "Monday through Friday" or "every 15 minutes"
+func (s *schedule) itemPhrases(field *cronField) (phrases []string, singles bool) {
+	singles = true
+	unit := field.kind.unit
+	for _, item := range field.items {
+		if item.special != "" {
+			continue
+		}
+		switch {
+		case item.all:
+			phrases = append(phrases, "every "+strconv.Itoa(item.step)+" "+plural(item.step, unit))
+			singles = false
+		case item.start == item.end:
+			phrases = append(phrases, s.valueName(field.kind, item.start))
+		case item.step == 1:
+			phrases = append(phrases, s.valueName(field.kind, item.start)+" through "+s.valueName(field.kind, item.end))
+			singles = false
+		default:
+			phrases = append(phrases, fmt.Sprintf("every %d %s from %s through %s", item.step, plural(item.step, unit),
+				s.valueName(field.kind, item.start), s.valueName(field.kind, item.end)))
+			singles = false
+		}
+	}
+	return phrases, singles
+}
+
+// fieldDescription describes a single field on its own
+func (s *schedule) fieldDescription(field *cronField) string {
+	if field.question {
+		return "any (no specific value)"
+	}
+	if field.isAny() {
+		return "every " + field.kind.unit
+	}
+	phrases, _ := s.itemPhrases(field)
+	phrases = append(phrases, s.specialPhrases(field)...)
+	return joinWords(phrases)
+}
+
+// specialPhrases renders the Quartz/AWS day extensions of a field
+func (s *schedule) specialPhrases(field *cronField) []string {
+	phrases := []string{}
+	for _, item := range field.items {
+		switch item.special {
+		case "L":
+			if item.n == 0 {
+				phrases = append(phrases, "the last day of the month")
+			} else {
+				phrases = append(phrases, fmt.Sprintf("%d %s before the last day of the month", item.n, plural(item.n, "day")))
+			}
+		case "LW":
+			phrases = append(phrases, "the last weekday of the month")
+		case "W":
+			phrases = append(phrases, fmt.Sprintf("the weekday nearest day %d of the month", item.n))
+		case "nL":
+			phrases = append(phrases, "the last "+s.valueName(field.kind, item.n)+" of the month")
+		case "#":
+			phrases = append(phrases, "the "+ordinals[item.nth]+" "+s.valueName(field.kind, item.n)+" of the month")
+		}
+	}
+	return phrases
+}
+
+// timePhrase describes the second, minute and hour fields
+func (s *schedule) timePhrase() string {
+	secondsZero := s.second == nil || (len(s.second.items) == 1 && s.second.items[0].start == 0 && s.second.items[0].end == 0 && !s.second.items[0].all)
+	secondSingle := s.second == nil || (len(s.second.items) == 1 && s.second.items[0].start == s.second.items[0].end && !s.second.items[0].all)
+	minutePhrases, minuteSingles := s.itemPhrases(s.minute)
+	hourPhrases, hourSingles := s.itemPhrases(s.hour)
+
+	// Fixed times of day: "At 09:00 and 17:30"
+	if secondSingle && minuteSingles && len(minutePhrases) == 1 && hourSingles {
+		times := []string{}
+		for _, hour := range s.hour.sorted() {
+			t := fmt.Sprintf("%02d:%02d", hour, s.minute.sorted()[0])
+			if !secondsZero {
+				t += fmt.Sprintf(":%02d", s.second.sorted()[0])
+			}
+			times = append(times, t)
+		}
+		return "at " + joinWords(times)
+	}
+
+	parts := []string{}
+	if !secondsZero {
+		secondPhrases, secondSingles := s.itemPhrases(s.second)
+		switch {
+		case s.second.isAny():
+			parts = append(parts, "every second")
+		case secondSingles:
+			parts = append(parts, "at "+plural(len(secondPhrases), "second")+" "+joinWords(secondPhrases))
+		default:
+			parts = append(parts, joinWords(secondPhrases))
+		}
+	}
+
+	switch {
+	case s.minute.isAny():
+		if secondsZero || !s.second.isAny() {
+			parts = append(parts, "every minute")
+		}
+	case minuteSingles:
+		parts = append(parts, "at "+plural(len(minutePhrases), "minute")+" "+joinWords(minutePhrases))
+	default:
+		parts = append(parts, joinWords(minutePhrases))
+	}
+
+	switch {
+	case s.hour.isAny():
+	case hourSingles:
+		parts = append(parts, "past "+plural(len(hourPhrases), "hour")+" "+strings.ReplaceAll(joinWords(hourPhrases), ":00", ""))
+	case len(s.hour.items) == 1 && s.hour.items[0].step == 1 && !s.hour.items[0].all:
+		parts = append(parts, fmt.Sprintf("between %02d:00 and %02d:59", s.hour.items[0].start, s.hour.items[0].end))
+	default:
+		parts = append(parts, joinWords(hourPhrases))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// dayPhrase describes the day_of_month and day_of_week fields
+func (s *schedule) dayPhrase() string {
+	var dayOfMonth, dayOfWeek string
+	if !s.dayOfMonth.question && !s.dayOfMonth.isAny() {
+		phrases, singles := s.itemPhrases(s.dayOfMonth)
+		if singles && len(phrases) > 0 {
+			phrases = []string{plural(len(phrases), "day") + " " + joinWords(phrases) + " of the month"}
+		} else if len(phrases) > 0 {
+			phrases = []string{joinWords(phrases) + " of the month"}
+		}
+		dayOfMonth = "on " + joinWords(append(phrases, s.specialPhrases(s.dayOfMonth)...))
+	}
+	if !s.dayOfWeek.question && !s.dayOfWeek.isAny() {
+		phrases, _ := s.itemPhrases(s.dayOfWeek)
+		dayOfWeek = "on " + joinWords(append(phrases, s.specialPhrases(s.dayOfWeek)...))
+	}
+
+	switch {
+	case dayOfMonth != "" && dayOfWeek != "":
+		// Both restricted only happens in standard cron, where either may match
+		return dayOfMonth + " or " + dayOfWeek
+	case dayOfMonth != "":
+		return dayOfMonth
+	}
+	return dayOfWeek
+}
+
+// describe renders the schedule in English, e.g. "At 09:00, on Monday through Friday"
+func (s *schedule) describe() string {
+	parts := []string{s.timePhrase()}
+	if day := s.dayPhrase(); day != "" {
+		parts = append(parts, day)
+	}
+	for _, field := range []*cronField{s.month, s.year} {
+		if field == nil || field.isAny() {
+			continue
+		}
+		phrases, singles := s.itemPhrases(field)
+		if singles || !strings.HasPrefix(phrases[0], "every") {
+			parts = append(parts, "in "+joinWords(phrases))
+		} else {
+			parts = append(parts, joinWords(phrases))
+		}
+	}
+
+	description := strings.Join(parts, ", ")
+	return strings.ToUpper(description[:1]) + description[1:]
+}
@@ -0,0 +1,371 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// fieldKind describes the range and names of a cron field
+type fieldKind struct {
+	name     string
+	unit     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	monthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	standardWeekdayNames = map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}
+	quartzWeekdayNames   = map[string]int{"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7}
+
+	secondField            = fieldKind{name: "second", unit: "second", min: 0, max: 59}
+	minuteField            = fieldKind{name: "minute", unit: "minute", min: 0, max: 59}
+	hourField              = fieldKind{name: "hour", unit: "hour", min: 0, max: 23}
+	dayOfMonthField        = fieldKind{name: "day_of_month", unit: "day", min: 1, max: 31}
+	monthField             = fieldKind{name: "month", unit: "month", min: 1, max: 12, names: monthNames}
+	standardDayOfWeekField = fieldKind{name: "day_of_week", unit: "day of the week", min: 0, max: 7, names: standardWeekdayNames}
+	quartzDayOfWeekField   = fieldKind{name: "day_of_week", unit: "day of the week", min: 1, max: 7, names: quartzWeekdayNames}
+	quartzYearField        = fieldKind{name: "year", unit: "year", min: 1970, max: 2099}
+	awsYearField           = fieldKind{name: "year", unit: "year", min: 1970, max: 2199}
+)
+
+// dialect describes the fields and extensions of one cron syntax
+type dialect struct {
+	name   string
+	fields []fieldKind
+	// optional is the number of trailing fields that may be omitted
+	optional int
+	// quartzDays enables '?', L, W and # in the day fields and requires one day field to be '?'
+	quartzDays bool
+}
+
+var dialects = map[string]dialect{
+	"standard": {name: "standard", fields: []fieldKind{minuteField, hourField, dayOfMonthField, monthField, standardDayOfWeekField}},
+	"seconds":  {name: "seconds", fields: []fieldKind{secondField, minuteField, hourField, dayOfMonthField, monthField, standardDayOfWeekField}},
+	"quartz":   {name: "quartz", fields: []fieldKind{secondField, minuteField, hourField, dayOfMonthField, monthField, quartzDayOfWeekField, quartzYearField}, optional: 1, quartzDays: true},
+	"aws":      {name: "aws", fields: []fieldKind{minuteField, hourField, dayOfMonthField, monthField, quartzDayOfWeekField, awsYearField}, quartzDays: true},
+}
+
+// macros are the standard cron shorthands, as 5-field expressions
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronItem is one comma-separated element of a field
+type cronItem struct {
+	start, end, step int
+	// all is set for '*' and '*/n'
+	all bool
+	// special is a day field extension: "L" (last day, n days before it), "LW" (last weekday),
+	// "W" (weekday nearest day n), "nL" (last weekday n of the month) or "#" (nth weekday n of the month)
+	special string
+	n, nth  int
+}
+
+// cronField is one parsed field of an expression
+type cronField struct {
+	kind  fieldKind
+	text  string
+	items []cronItem
+	// star is set when the field starts with '*', which selects the Vixie day-of-month/day-of-week rule
+	star bool
+	// question is set for '?', meaning no specific value
+	question bool
+	// values marks the values matched by plain items, indexed from kind.min
+	values []bool
+}
+
+// has reports whether a plain item of the field matches v
+func (f *cronField) has(v int) bool {
+	if f.question {
+		return true
+	}
+	return v >= f.kind.min && v <= f.kind.max && f.values[v-f.kind.min]
+}
+
+// sorted returns the values matched by plain items in ascending order
+func (f *cronField) sorted() []int {
+	values := []int{}
+	for i, ok := range f.values {
+		if ok {
+			values = append(values, f.kind.min+i)
+		}
+	}
+	return values
+}
+
+// isAny reports whether the field matches every value
+func (f *cronField) isAny() bool {
+	return f.question || (len(f.items) == 1 && f.items[0].all && f.items[0].step == 1)
+}
+
+// schedule is a parsed cron expression
+type schedule struct {
+	dialect    dialect
+	normalized string
+	fields     []*cronField
+	// second and year are nil when the dialect or expression has no such field
+	second, minute, hour, dayOfMonth, month, dayOfWeek, year *cronField
+}
+
+// parseExpression parses a cron expression in the named dialect, detecting the dialect when empty
+func parseExpression(expression, dialectName string) (*schedule, error) {
+	s := strings.TrimSpace(expression)
+	lower := strings.ToLower(s)
+
+	if strings.HasPrefix(lower, "rate(") {
+		return nil, errors.New("rate() expressions are not cron expressions")
+	}
+	if strings.HasPrefix(lower, "cron(") && strings.HasSuffix(s, ")") {
+		if dialectName != "" && dialectName != "aws" {
+			return nil, errors.New("cron(...) expressions are only valid in the aws dialect")
+		}
+		s, dialectName = strings.TrimSpace(s[5:len(s)-1]), "aws"
+	}
+
+	if strings.HasPrefix(s, "@") {
+		if dialectName != "" && dialectName != "standard" && dialectName != "seconds" {
+			return nil, errors.New("macros like " + s + " are only valid in the standard and seconds dialects")
+		}
+		if lower == "@reboot" {
+			return nil, errors.New("@reboot runs at startup and has no schedule")
+		}
+		expanded, ok := macros[lower]
+		if !ok {
+			return nil, errors.New("unknown macro: " + s)
+		}
+		s = expanded
+		if dialectName == "seconds" {
+			s = "0 " + s
+		}
+	}
+
+	parts := strings.Fields(s)
+	if dialectName == "" {
+		dialectName = detectDialect(parts)
+	}
+	d := dialects[dialectName]
+	if len(parts) < len(d.fields)-d.optional || len(parts) > len(d.fields) {
+		expected := strconv.Itoa(len(d.fields))
+		if d.optional > 0 {
+			expected = strconv.Itoa(len(d.fields)-d.optional) + " or " + expected
+		}
+		return nil, fmt.Errorf("%s cron expressions have %s fields, got %d", d.name, expected, len(parts))
+	}
+
+	sched := &schedule{dialect: d, normalized: strings.Join(parts, " ")}
+	for i, part := range parts {
+		field, err := parseField(part, d.fields[i], d.quartzDays)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s field %q: %w", d.fields[i].name, part, err)
+		}
+		sched.fields = append(sched.fields, field)
+		switch field.kind.name {
+		case "second":
+			sched.second = field
+		case "minute":
+			sched.minute = field
+		case "hour":
+			sched.hour = field
+		case "day_of_month":
+			sched.dayOfMonth = field
+		case "month":
+			sched.month = field
+		case "day_of_week":
+			sched.dayOfWeek = field
+		case "year":
+			sched.year = field
+		}
+	}
+
+	if d.quartzDays && sched.dayOfMonth.question == sched.dayOfWeek.question {
+		return nil, errors.New("exactly one of day_of_month and day_of_week must be '?'")
+	}
+	return sched, nil
+}
+
+// detectDialect guesses the dialect from the field count: 5 is standard, 7 is Quartz, and 6 is AWS when
+// its day fields (the 3rd or 5th) are '?' and the last field is a year, Quartz when another field is '?',
+// and standard with seconds otherwise
+func detectDialect(parts []string) string {
+	switch len(parts) {
+	case 5:
+		return "standard"
+	case 6:
+		if (parts[2] == "?" || parts[4] == "?") && isYearField(parts[5]) {
+			return "aws"
+		}
+		if slices.Contains(parts, "?") {
+			return "quartz"
+		}
+		return "seconds"
+	case 7:
+		return "quartz"
+	}
+	return "standard"
+}
+
+// isYearField reports whether a field could be a year field: '*' or years with ranges, steps and lists
+func isYearField(text string) bool {
+	return text != "" && strings.Trim(text, "0123456789*,-/") == ""
+}
+
+// parseField parses one field: a comma-separated list of values, ranges and steps
+func parseField(text string, kind fieldKind, quartzDays bool) (*cronField, error) {
+	field := &cronField{kind: kind, text: text, star: strings.HasPrefix(text, "*"), values: make([]bool, kind.max-kind.min+1)}
+	dayField := kind.name == "day_of_month" || kind.name == "day_of_week"
+
+	if text == "?" {
+		if !quartzDays || !dayField {
+			return nil, errors.New("'?' is only allowed in the day fields of quartz and aws expressions")
+		}
+		field.question = true
+		return field, nil
+	}
+
+	for _, part := range strings.Split(text, ",") {
+		item, err := parseItem(strings.ToUpper(part), kind, quartzDays && dayField)
+		if err != nil {
+			return nil, err
+		}
+		field.items = append(field.items, item)
+		if item.special != "" {
+			continue
+		}
+
+		// Mark every value the item covers, wrapping around for ranges like FRI-MON
+		span := kind.max - kind.min + 1
+		length := (item.end - item.start + span) % span
+		for i := 0; i <= length; i += item.step {
+			field.values[(item.start-kind.min+i)%span] = true
+		}
+	}
+	return field, nil
+}
+
+// parseItem parses one list element: "*", "n", "a-b", any of those with "/step", or a day field extension
+func parseItem(text string, kind fieldKind, extensions bool) (cronItem, error) {
+	if text == "" {
+		return cronItem{}, errors.New("empty list element")
+	}
+
+	if extensions {
+		if item, ok, err := parseDayExtension(text, kind); ok || err != nil {
+			return item, err
+		}
+	}
+
+	base, stepText, hasStep := strings.Cut(text, "/")
+	item := cronItem{step: 1}
+	if hasStep {
+		step, err := strconv.Atoi(stepText)
+		if err != nil || step < 1 || step > kind.max-kind.min+1 {
+			return cronItem{}, fmt.Errorf("step must be between 1 and %d", kind.max-kind.min+1)
+		}
+		item.step = step
+	}
+
+	var err error
+	switch {
+	case base == "*":
+		item.all, item.start, item.end = true, kind.min, kind.max
+	case strings.Contains(base, "-"):
+		low, high, _ := strings.Cut(base, "-")
+		if item.start, err = parseValue(low, kind); err != nil {
+			return cronItem{}, err
+		}
+		if item.end, err = parseValue(high, kind); err != nil {
+			return cronItem{}, err
+		}
+		if item.start > item.end && !extensions {
+			return cronItem{}, fmt.Errorf("range %s goes backwards", base)
+		}
+	default:
+		if item.start, err = parseValue(base, kind); err != nil {
+			return cronItem{}, err
+		}
+		item.end = item.start
+		if hasStep {
+			// "a/n" means every n starting at a
+			item.end = kind.max
+		}
+	}
+	return item, nil
+}
+
+// parseDayExtension parses the Quartz/AWS day field extensions: L, L-n, LW and nW in day_of_month,
+// and L, nL and n#k in day_of_week; ok is false when text is not an extension
+func parseDayExtension(text string, kind fieldKind) (cronItem, bool, error) {
+	if kind.name == "day_of_month" {
+		switch {
+		case text == "L":
+			return cronItem{special: "L"}, true, nil
+		case text == "LW":
+			return cronItem{special: "LW"}, true, nil
+		case strings.HasPrefix(text, "L-"):
+			n, err := strconv.Atoi(text[2:])
+			if err != nil || n < 0 || n > 30 {
+				return cronItem{}, true, errors.New("L-n offset must be between 0 and 30")
+			}
+			return cronItem{special: "L", n: n}, true, nil
+		case strings.HasSuffix(text, "W"):
+			n, err := parseValue(text[:len(text)-1], kind)
+			if err != nil {
+				return cronItem{}, true, err
+			}
+			return cronItem{special: "W", n: n}, true, nil
+		}
+		return cronItem{}, false, nil
+	}
+
+	switch {
+	case text == "L":
+		// A bare L in day_of_week is the last day of the week, Saturday
+		return cronItem{start: 7, end: 7, step: 1}, true, nil
+	case strings.HasSuffix(text, "L"):
+		n, err := parseValue(text[:len(text)-1], kind)
+		if err != nil {
+			return cronItem{}, true, err
+		}
+		return cronItem{special: "nL", n: n}, true, nil
+	case strings.Contains(text, "#"):
+		weekday, nthText, _ := strings.Cut(text, "#")
+		n, err := parseValue(weekday, kind)
+		if err != nil {
+			return cronItem{}, true, err
+		}
+		nth, err := strconv.Atoi(nthText)
+		if err != nil || nth < 1 || nth > 5 {
+			return cronItem{}, true, errors.New("# occurrence must be between 1 and 5")
+		}
+		return cronItem{special: "#", n: n, nth: nth}, true, nil
+	}
+	return cronItem{}, false, nil
+}
+
+// parseValue parses a number or name within the field's range
+func parseValue(text string, kind fieldKind) (int, error) {
+	if v, ok := kind.names[text]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a valid %s", text, kind.unit)
+	}
+	if v < kind.min || v > kind.max {
+		return 0, fmt.Errorf("%d is out of range %d-%d", v, kind.min, kind.max)
+	}
+	return v, nil
+}
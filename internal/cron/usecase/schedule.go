@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"slices"
+	"time"
+
+	timestampusecase "konverter/internal/timestamp/usecase"
+)
+
+// maxSearchDays bounds the search for fire times; 400 years covers every Gregorian calendar pattern
+const maxSearchDays = 146097
+
+// run is one fire time and how a DST change affected it
+type run struct {
+	time    time.Time
+	dstNote string
+}
+
+// weekdayValue converts a weekday to the dialect's day_of_week numbering
+func (s *schedule) weekdayValue(weekday time.Weekday) int {
+	if s.dialect.quartzDays {
+		return int(weekday) + 1
+	}
+	return int(weekday)
+}
+
+// matchesDayOfMonth reports whether the day_of_month field matches date
+func (s *schedule) matchesDayOfMonth(date time.Time) bool {
+	field := s.dayOfMonth
+	day := date.Day()
+	if field.has(day) {
+		return true
+	}
+
+	last := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, item := range field.items {
+		switch item.special {
+		case "L":
+			if day == last-item.n {
+				return true
+			}
+		case "LW":
+			if day == nearestWeekday(date, last, last) {
+				return true
+			}
+		case "W":
+			if item.n <= last && day == nearestWeekday(date, item.n, last) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nearestWeekday returns the Monday-to-Friday day nearest to target without leaving the month
+func nearestWeekday(date time.Time, target, last int) int {
+	switch time.Date(date.Year(), date.Month(), target, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if target == 1 {
+			return 3
+		}
+		return target - 1
+	case time.Sunday:
+		if target == last {
+			return target - 2
+		}
+		return target + 1
+	}
+	return target
+}
+
+// matchesDayOfWeek reports whether the day_of_week field matches date
+func (s *schedule) matchesDayOfWeek(date time.Time) bool {
+	field := s.dayOfWeek
+	value := s.weekdayValue(date.Weekday())
+	// Standard cron accepts 7 as well as 0 for Sunday
+	if field.has(value) || (!s.dialect.quartzDays && value == 0 && field.has(7)) {
+		return true
+	}
+
+	last := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, item := range field.items {
+		switch item.special {
+		case "nL":
+			if value == item.n && date.Day()+7 > last {
+				return true
+			}
+		case "#":
+			if value == item.n && (date.Day()-1)/7+1 == item.nth {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesDay reports whether the schedule fires on date
+func (s *schedule) matchesDay(date time.Time) bool {
+	if s.year != nil && !s.year.has(date.Year()) {
+		return false
+	}
+	if !s.month.has(int(date.Month())) {
+		return false
+	}
+
+	dayOfMonth, dayOfWeek := s.matchesDayOfMonth(date), s.matchesDayOfWeek(date)
+	// Vixie cron fires when either day field matches if both are restricted
+	if !s.dialect.quartzDays && !s.dayOfMonth.star && !s.dayOfWeek.star {
+		return dayOfMonth || dayOfWeek
+	}
+	return dayOfMonth && dayOfWeek
+}
+
+// yearBounds returns the first and last year the schedule can fire in, if it has a year field
+func (s *schedule) yearBounds() (int, int, bool) {
+	if s.year == nil {
+		return 0, 0, false
+	}
+	years := s.year.sorted()
+	if len(years) == 0 {
+		return 0, 0, false
+	}
+	return years[0], years[len(years)-1], true
+}
+
+// runs lists up to count fire times strictly after (forward) or before from, computed on wall clocks in loc
+// Wall clocks skipped by a DST change fire when the clock changes; repeated wall clocks fire once, the first time
+func (s *schedule) runs(from time.Time, loc *time.Location, count int, forward bool) []run {
+	seconds := []int{0}
+	if s.second != nil {
+		seconds = s.second.sorted()
+	}
+	minutes, hours := s.minute.sorted(), s.hour.sorted()
+	step := 1
+	if !forward {
+		slices.Reverse(seconds)
+		slices.Reverse(minutes)
+		slices.Reverse(hours)
+		step = -1
+	}
+	firstYear, lastYear, bounded := s.yearBounds()
+
+	local := from.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	runs := []run{}
+	for i := 0; i < maxSearchDays && len(runs) < count; i, day = i+1, day.AddDate(0, 0, step) {
+		if bounded && ((forward && day.Year() > lastYear) || (!forward && day.Year() < firstYear)) {
+			break
+		}
+		if !s.matchesDay(day) {
+			continue
+		}
+
+		for _, hour := range hours {
+			for _, minute := range minutes {
+				for _, second := range seconds {
+					wall := day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second)
+					instant, status, candidates := timestampusecase.LocalizeWallClock(wall, loc)
+
+					note := ""
+					switch status {
+					case "gap":
+						instant = timestampusecase.FindZoneTransition(candidates[1], candidates[0], loc)
+						note = wall.Format("15:04:05") + " does not exist on this day (DST gap); runs when the clock changes"
+					case "ambiguous":
+						note = wall.Format("15:04:05") + " occurs twice on this day (DST overlap); runs once, at the first occurrence"
+					}
+
+					if (forward && !instant.After(from)) || (!forward && !instant.Before(from)) {
+						continue
+					}
+					// Skipped wall clocks collapse onto the moment of the change
+					if len(runs) > 0 && runs[len(runs)-1].time.Equal(instant) {
+						continue
+					}
+					runs = append(runs, run{time: instant, dstNote: note})
+					if len(runs) == count {
+						return runs
+					}
+				}
+			}
+		}
+	}
+	return runs
+}
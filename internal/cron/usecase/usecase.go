@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	cronmodels "konverter/internal/cron/models"
+)
+
+// Explain parses a cron expression and describes it in English
+func Explain(req cronmodels.ExplainRequest) (cronmodels.ExplainResponse, error) {
+	if err := req.Validate(); err != nil {
+		return cronmodels.ExplainResponse{}, err
+	}
+
+	sched, err := parseExpression(req.Expression, req.Dialect)
+	if err != nil {
+		return cronmodels.ExplainResponse{}, err
+	}
+
+	response := cronmodels.ExplainResponse{
+		Expression:  req.Expression,
+		Dialect:     sched.dialect.name,
+		Normalized:  sched.normalized,
+		Fields:      []cronmodels.Field{},
+		Description: sched.describe(),
+	}
+	for _, field := range sched.fields {
+		response.Fields = append(response.Fields, cronmodels.Field{
+			Name:        field.kind.name,
+			Value:       field.text,
+			Description: sched.fieldDescription(field),
+		})
+	}
+
+	return response, nil
+}
+
+// Validate reports whether a cron expression is valid, returning the parse error as data rather than failing
+func Validate(req cronmodels.ExplainRequest) (cronmodels.ValidateResponse, error) {
+	if err := req.Validate(); err != nil {
+		return cronmodels.ValidateResponse{}, err
+	}
+
+	sched, err := parseExpression(req.Expression, req.Dialect)
+	if err != nil {
+		return cronmodels.ValidateResponse{Valid: false, Dialect: req.Dialect, Error: err.Error()}, nil
+	}
+	return cronmodels.ValidateResponse{Valid: true, Dialect: sched.dialect.name}, nil
+}
+
+// Schedule lists the next or previous fire times of a cron expression in a timezone
+func Schedule(req cronmodels.ScheduleRequest) (cronmodels.ScheduleResponse, error) {
+	if err := req.Validate(); err != nil {
+		return cronmodels.ScheduleResponse{}, err
+	}
+
+	sched, err := parseExpression(req.Expression, req.Dialect)
+	if err != nil {
+		return cronmodels.ScheduleResponse{}, err
+	}
+
+	loc := time.UTC
+	if req.Timezone != "" {
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return cronmodels.ScheduleResponse{}, errors.New("invalid timezone: " + err.Error())
+		}
+	}
+
+	from := time.Now()
+	if req.From != "" {
+		if from, err = parseFrom(req.From); err != nil {
+			return cronmodels.ScheduleResponse{}, err
+		}
+	}
+
+	count := req.Count
+	if count == 0 {
+		count = cronmodels.DefaultRunCount
+	}
+	direction := req.Direction
+	if direction == "" {
+		direction = "next"
+	}
+
+	response := cronmodels.ScheduleResponse{
+		Expression:  req.Expression,
+		Dialect:     sched.dialect.name,
+		Description: sched.describe(),
+		Timezone:    loc.String(),
+		Direction:   direction,
+		Runs:        []cronmodels.Run{},
+	}
+	for _, r := range sched.runs(from, loc, count, direction == "next") {
+		response.Runs = append(response.Runs, cronmodels.Run{
+			Time:    r.time.In(loc).Format(time.RFC3339),
+			GMT:     r.time.UTC().Format(time.RFC3339),
+			Seconds: r.time.Unix(),
+			DSTNote: r.dstNote,
+		})
+	}
+
+	return response, nil
+}
+
+// parseFrom parses the search start as RFC3339 or Unix seconds
+func parseFrom(from string) (time.Time, error) {
+	from = strings.TrimSpace(from)
+	if seconds, err := strconv.ParseInt(from, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, from)
+	if err != nil {
+		return time.Time{}, errors.New("from must be RFC3339 or Unix seconds")
+	}
+	return t, nil
+}
@@ -36,3 +36,16 @@ func Decrypt(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
 }
 
+func Inspect(c *fiber.Ctx) error {
+	req := cryptomodels.InspectRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	res, err := usecase.Inspect(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
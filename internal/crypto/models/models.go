@@ -2,19 +2,59 @@ package models
 
 import (
 	"errors"
+	"slices"
 	"unicode/utf8"
 )
 
 const (
 	MaxTextSize     = 10 * 1024 * 1024 // 10MB in bytes
 	MinSecretLength = 8
+	MaxKeyIDLength  = 255     // Bytes of key_id recorded in the ciphertext
+	MaxSaltLength   = 1 << 16 // Bytes of salt and of ctx_info, which are recorded in the ciphertext
 )
 
+// Encodings are the binary-to-text encodings of ciphertexts; plaintexts may also be "text" (UTF-8, the default)
+var Encodings = []string{"std", "url", "raw", "rawurl", "hex"}
+
+// validateEncoding checks an encoding name, allowing "text" when plaintext is set
+func validateEncoding(field, encoding string, plaintext bool) error {
+	if encoding == "" || slices.Contains(Encodings, encoding) || (plaintext && encoding == "text") {
+		return nil
+	}
+	allowed := "'std', 'url', 'raw', 'rawurl' or 'hex'"
+	if plaintext {
+		allowed = "'text', " + allowed
+	}
+	return errors.New(field + " must be one of " + allowed)
+}
+
+// KDFParams tunes a password-based key derivation; zero fields take the KDF's defaults
+type KDFParams struct {
+	Iterations  uint32 `json:"iterations,omitempty"`  // Argon2id passes (default 3) or PBKDF2 iterations (default 600000)
+	MemoryKiB   uint32 `json:"memory_kib,omitempty"`  // Argon2id memory in KiB (default 65536)
+	Parallelism uint8  `json:"parallelism,omitempty"` // Argon2id lanes (default 4) or scrypt p (default 1)
+	CostLog2    uint8  `json:"cost_log2,omitempty"`   // scrypt N as a power of two (default 15)
+	BlockSize   uint32 `json:"block_size,omitempty"`  // scrypt r (default 8)
+}
+
 type EncryptRequest struct {
-	Text    string `json:"text"`    // Plain text to encrypt (max 10MB)
-	Secret  string `json:"secret"`  // Encryption secret (min 8 characters)
-	Salt    string `json:"salt"`    // Salt for HKDF key derivation (optional)
-	CtxInfo string `json:"ctx_info"` // Context info for HKDF key derivation (optional)
+	Text      string `json:"text"`      // Plain text to encrypt (max 10MB)
+	Secret    string `json:"secret"`    // Encryption secret (min 8 characters)
+	Salt      string `json:"salt"`      // Salt for HKDF key derivation (optional)
+	CtxInfo   string `json:"ctx_info"`  // Context info for HKDF key derivation (optional)
+	Algorithm string `json:"algorithm"` // AEAD cipher (optional): aes-128/192/256-gcm (default aes-256-gcm), [x]chacha20-poly1305, aes-128/256-gcm-siv
+	// InputEncoding is how Text is encoded (optional): "text" (default) or, for binary plaintext, an entry of Encodings
+	InputEncoding string `json:"input_encoding"`
+	// OutputEncoding is how the ciphertext is encoded (optional): an entry of Encodings, "std" by default
+	OutputEncoding string `json:"output_encoding"`
+	// KDF is the password-based key derivation (optional): "argon2id", "scrypt" or "pbkdf2", with a random salt
+	// stored in the ciphertext; by default the key is a SHA-256 of the secret, or HKDF when Salt and CtxInfo are set
+	KDF       string    `json:"kdf"`
+	KDFParams KDFParams `json:"kdf_params"` // Tunes KDF (optional); stored in the ciphertext
+	KeyID     string    `json:"key_id"`     // Names the secret (optional), e.g. for key rotation; stored in the ciphertext
+	// AAD is associated data (optional), e.g. a tenant ID or record key, that is authenticated but not stored:
+	// decrypting requires the same aad
+	AAD string `json:"aad"`
 }
 
 func (r *EncryptRequest) Validate() error {
@@ -36,14 +76,39 @@ func (r *EncryptRequest) Validate() error {
 		return errors.New("text size exceeds maximum limit of 10MB")
 	}
 
-	return nil
+	if r.KDF != "" && (r.Salt != "" || r.CtxInfo != "") {
+		return errors.New("salt and ctx_info only apply without kdf; password KDFs use a random salt")
+	}
+
+	if r.KDF == "" && r.KDFParams != (KDFParams{}) {
+		return errors.New("kdf_params requires kdf")
+	}
+
+	if len(r.Salt) >= MaxSaltLength || len(r.CtxInfo) >= MaxSaltLength {
+		return errors.New("salt and ctx_info must be less than 64KB")
+	}
+
+	if len(r.KeyID) > MaxKeyIDLength {
+		return errors.New("key_id must be at most 255 bytes")
+	}
+
+	if err := validateEncoding("input_encoding", r.InputEncoding, true); err != nil {
+		return err
+	}
+	return validateEncoding("output_encoding", r.OutputEncoding, false)
 }
 
 type DecryptRequest struct {
-	Text    string `json:"text"`    // Base64 encoded encrypted text
-	Secret  string `json:"secret"`  // Decryption secret (min 8 characters)
-	Salt    string `json:"salt"`    // Salt used during encryption (optional)
-	CtxInfo string `json:"ctx_info"` // Context info used during encryption (optional)
+	Text      string `json:"text"`      // Base64 encoded encrypted text
+	Secret    string `json:"secret"`    // Decryption secret (min 8 characters)
+	Salt      string `json:"salt"`      // Salt used during encryption (optional); only for ciphertexts that do not record it
+	CtxInfo   string `json:"ctx_info"`  // Context info used during encryption (optional); only for ciphertexts that do not record it
+	Algorithm string `json:"algorithm"` // AEAD cipher (optional): must match the ciphertext header, or is used when there is none
+	// InputEncoding is how Text is encoded (optional): an entry of Encodings; by default either base64 alphabet, padded or not
+	InputEncoding string `json:"input_encoding"`
+	// OutputEncoding is how the decrypted text is returned (optional): "text" (default) or, for binary plaintext, an entry of Encodings
+	OutputEncoding string `json:"output_encoding"`
+	AAD            string `json:"aad"` // Associated data given when encrypting (optional)
 }
 
 func (r *DecryptRequest) Validate() error {
@@ -59,14 +124,53 @@ func (r *DecryptRequest) Validate() error {
 		return errors.New("secret must be at least 8 characters")
 	}
 
-	return nil
+	if err := validateEncoding("input_encoding", r.InputEncoding, false); err != nil {
+		return err
+	}
+	return validateEncoding("output_encoding", r.OutputEncoding, true)
 }
 
 type EncryptResponse struct {
 	EncryptedText string `json:"encrypted_text"`
+	Algorithm     string `json:"algorithm"` // AEAD cipher used, recorded in the ciphertext header
+	KDF           string `json:"kdf"`       // Key derivation used: "sha256", "hkdf-sha256", "argon2id", "scrypt" or "pbkdf2-sha256"
+	KeyID         string `json:"key_id,omitempty"`
 }
 
 type DecryptResponse struct {
 	DecryptedText string `json:"decrypted_text"`
+	Algorithm     string `json:"algorithm"`        // AEAD cipher the text was decrypted with
+	KDF           string `json:"kdf"`              // Key derivation the key was derived with
+	KeyID         string `json:"key_id,omitempty"` // Key id recorded in the ciphertext
+}
+
+type InspectRequest struct {
+	Text string `json:"text"` // Encoded ciphertext
+	// InputEncoding is how Text is encoded (optional): an entry of Encodings; by default either base64 alphabet, padded or not
+	InputEncoding string `json:"input_encoding"`
 }
 
+func (r *InspectRequest) Validate() error {
+	if r.Text == "" {
+		return errors.New("text is required")
+	}
+	return validateEncoding("input_encoding", r.InputEncoding, false)
+}
+
+// InspectResponse describes a ciphertext as its header records it, without decrypting it
+type InspectResponse struct {
+	Version   int        `json:"version"`              // Header version, 0 for a ciphertext without a header
+	Algorithm string     `json:"algorithm"`            // AEAD cipher
+	KDF       string     `json:"kdf,omitempty"`        // Key derivation; empty when the decrypt request decides it
+	KDFParams *KDFParams `json:"kdf_params,omitempty"` // Password KDF parameters
+	Salt      string     `json:"salt,omitempty"`       // Hex-encoded password KDF or HKDF salt
+	CtxInfo   string     `json:"ctx_info,omitempty"`   // HKDF context info
+	KeyID     string     `json:"key_id,omitempty"`
+	// Sizes of the parts of the ciphertext, in bytes
+	HeaderSize    int      `json:"header_size"`
+	NonceSize     int      `json:"nonce_size"`
+	Nonce         string   `json:"nonce"` // Hex-encoded
+	TagSize       int      `json:"tag_size"`
+	PlaintextSize int      `json:"plaintext_size"`
+	Notes         []string `json:"notes,omitempty"`
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// defaultAlgorithm is used to encrypt when no algorithm is given, and to decrypt ciphertexts without a header
+const defaultAlgorithm = "aes-256-gcm"
+
+// aeadAlgorithm is an authenticated cipher and the key size it is used with
+type aeadAlgorithm struct {
+	// id identifies the algorithm in ciphertext headers and must never change
+	id      byte
+	name    string
+	keySize int
+	newAEAD func(key []byte) (cipher.AEAD, error)
+}
+
+// aeadAlgorithms are the supported algorithms, in the order they are listed in errors
+var aeadAlgorithms = []aeadAlgorithm{
+	{1, "aes-128-gcm", 16, newAESGCM},
+	{2, "aes-192-gcm", 24, newAESGCM},
+	{3, "aes-256-gcm", 32, newAESGCM},
+	{4, "chacha20-poly1305", chacha20poly1305.KeySize, chacha20poly1305.New},
+	{5, "xchacha20-poly1305", chacha20poly1305.KeySize, chacha20poly1305.NewX},
+	{6, "aes-128-gcm-siv", 16, newGCMSIV},
+	{7, "aes-256-gcm-siv", 32, newGCMSIV},
+}
+
+// algorithmAliases maps alternative spellings to algorithm names
+var algorithmAliases = map[string]string{
+	"aes-gcm-siv":       "aes-256-gcm-siv",
+	"chacha20poly1305":  "chacha20-poly1305",
+	"xchacha20poly1305": "xchacha20-poly1305",
+}
+
+// newAESGCM returns AES-GCM with the key size given by key
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// lookupAlgorithm finds an algorithm by name, case-insensitively and accepting '_' for '-'
+func lookupAlgorithm(name string) (aeadAlgorithm, error) {
+	normalized := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "_", "-")
+	if alias, ok := algorithmAliases[normalized]; ok {
+		normalized = alias
+	}
+	for _, algorithm := range aeadAlgorithms {
+		if algorithm.name == normalized {
+			return algorithm, nil
+		}
+	}
+
+	names := make([]string, 0, len(aeadAlgorithms))
+	for _, algorithm := range aeadAlgorithms {
+		names = append(names, algorithm.name)
+	}
+	return aeadAlgorithm{}, errors.New("unsupported algorithm: " + name + ", expected one of " + strings.Join(names, ", "))
+}
+
+// algorithmByID finds the algorithm a ciphertext header names
+func algorithmByID(id byte) (aeadAlgorithm, bool) {
+	for _, algorithm := range aeadAlgorithms {
+		if algorithm.id == id {
+			return algorithm, true
+		}
+	}
+	return aeadAlgorithm{}, false
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"errors"
+
+	encodingusecase "konverter/internal/encoding/usecase"
+)
+
+// codecNames maps the encoding names of crypto requests to the shared encoding codecs; the base64 codecs
+// accept either alphabet, padded or not, and the hex codec tolerates whitespace and a 0x prefix
+var codecNames = map[string]string{
+	"":       "base64",
+	"std":    "base64",
+	"url":    "base64url",
+	"raw":    "base64-raw",
+	"rawurl": "base64url-raw",
+	"hex":    "hex",
+	"text":   "text",
+}
+
+// encodeBytes encodes data as "std" (the default), "url", "raw", "rawurl" or "hex" text, or as "text" UTF-8
+func encodeBytes(data []byte, encoding string) (string, error) {
+	return encodingusecase.Encode(codecNames[encoding], data)
+}
+
+// decodeBytes decodes text in the given encoding; an empty encoding accepts either base64 alphabet
+func decodeBytes(text, encoding string) ([]byte, error) {
+	name := codecNames[encoding]
+	data, err := encodingusecase.Decode(name, text)
+	if err != nil {
+		if name == "hex" {
+			return nil, errors.New("failed to decode hex: " + err.Error())
+		}
+		return nil, errors.New("failed to decode Base64: " + err.Error())
+	}
+	return data, nil
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+// gcmSIV implements AES-GCM-SIV (RFC 8452), a nonce-misuse-resistant AEAD
+// Per-nonce authentication and encryption keys are derived from the key-generating key
+//
+// It is kept in-tree because neither the standard library nor golang.org/x/crypto has AES-GCM-SIV, and the
+// maintained implementations, such as Tink's, only seal with a nonce they draw themselves rather than as a
+// cipher.AEAD, which every algorithm here must be; gcmsiv_test.go checks it against all of RFC 8452 Appendix C
+// and the Wycheproof counter overflow cases
+type gcmSIV struct {
+	block cipher.Block
+	// keySize is the size of the key-generating key and the derived encryption key, 16 or 32 bytes
+	keySize int
+}
+
+const (
+	gcmSIVNonceSize = 12
+	gcmSIVTagSize   = 16
+	// gcmSIVMaxPlaintext is the RFC 8452 limit of 2^36 bytes
+	gcmSIVMaxPlaintext = 1 << 36
+)
+
+// newGCMSIV returns AES-128-GCM-SIV or AES-256-GCM-SIV for a 16 or 32 byte key
+func newGCMSIV(key []byte) (cipher.AEAD, error) {
+	if len(key) != 16 && len(key) != 32 {
+		return nil, errors.New("AES-GCM-SIV key must be 16 or 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return &gcmSIV{block: block, keySize: len(key)}, nil
+}
+
+func (g *gcmSIV) NonceSize() int { return gcmSIVNonceSize }
+
+func (g *gcmSIV) Overhead() int { return gcmSIVTagSize }
+
+// deriveKeys derives the per-nonce POLYVAL key and encryption cipher
+func (g *gcmSIV) deriveKeys(nonce []byte) ([]byte, cipher.Block) {
+	var input, output [16]byte
+	copy(input[4:], nonce)
+
+	keys := make([]byte, 0, 16+g.keySize)
+	for i := uint32(0); len(keys) < 16+g.keySize; i++ {
+		binary.LittleEndian.PutUint32(input[:4], i)
+		g.block.Encrypt(output[:], input[:])
+		keys = append(keys, output[:8]...)
+	}
+
+	// aes.NewCipher only fails on invalid key sizes
+	block, _ := aes.NewCipher(keys[16:])
+	return keys[:16], block
+}
+
+// tag computes the tag over additionalData and plaintext
+func (g *gcmSIV) tag(authKey []byte, block cipher.Block, nonce, plaintext, additionalData []byte) [16]byte {
+	var p polyval
+	p.init(authKey)
+	p.update(additionalData)
+	p.update(plaintext)
+
+	var lengths [16]byte
+	binary.LittleEndian.PutUint64(lengths[:8], uint64(len(additionalData))*8)
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(plaintext))*8)
+	p.update(lengths[:])
+
+	s := p.sum()
+	for i := range nonce {
+		s[i] ^= nonce[i]
+	}
+	s[15] &= 0x7f
+
+	var tag [16]byte
+	block.Encrypt(tag[:], s[:])
+	return tag
+}
+
+// ctr XORs in with the keystream counted from the tag, whose first 32 bits are a little-endian counter
+func ctr(block cipher.Block, tag [16]byte, out, in []byte) {
+	counter := tag
+	counter[15] |= 0x80
+	var keystream [16]byte
+	for len(in) > 0 {
+		block.Encrypt(keystream[:], counter[:])
+		binary.LittleEndian.PutUint32(counter[:4], binary.LittleEndian.Uint32(counter[:4])+1)
+		n := subtle.XORBytes(out, in, keystream[:])
+		out, in = out[n:], in[n:]
+	}
+}
+
+func (g *gcmSIV) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != gcmSIVNonceSize {
+		panic("gcmsiv: incorrect nonce length given to GCM-SIV")
+	}
+	if uint64(len(plaintext)) > gcmSIVMaxPlaintext {
+		panic("gcmsiv: message too large for GCM-SIV")
+	}
+
+	authKey, block := g.deriveKeys(nonce)
+	tag := g.tag(authKey, block, nonce, plaintext, additionalData)
+
+	ret, out := sliceForAppend(dst, len(plaintext)+gcmSIVTagSize)
+	ctr(block, tag, out, plaintext)
+	copy(out[len(plaintext):], tag[:])
+	return ret
+}
+
+func (g *gcmSIV) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != gcmSIVNonceSize {
+		panic("gcmsiv: incorrect nonce length given to GCM-SIV")
+	}
+	if len(ciphertext) < gcmSIVTagSize || uint64(len(ciphertext)) > gcmSIVMaxPlaintext+gcmSIVTagSize {
+		return nil, errors.New("cipher: message authentication failed")
+	}
+
+	var tag [16]byte
+	copy(tag[:], ciphertext[len(ciphertext)-gcmSIVTagSize:])
+	ciphertext = ciphertext[:len(ciphertext)-gcmSIVTagSize]
+
+	authKey, block := g.deriveKeys(nonce)
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	ctr(block, tag, out, ciphertext)
+
+	expected := g.tag(authKey, block, nonce, out, additionalData)
+	if subtle.ConstantTimeCompare(expected[:], tag[:]) != 1 {
+		clear(out)
+		return nil, errors.New("cipher: message authentication failed")
+	}
+	return ret, nil
+}
+
+// sliceForAppend extends in by n bytes, returning the whole slice and the new tail
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	return head, head[len(in):]
+}
+
+// polyval is the POLYVAL universal hash of RFC 8452, over GF(2^128) in little-endian bit order
+type polyval struct {
+	hLo, hHi uint64
+	sLo, sHi uint64
+}
+
+func (p *polyval) init(key []byte) {
+	p.hLo = binary.LittleEndian.Uint64(key[:8])
+	p.hHi = binary.LittleEndian.Uint64(key[8:])
+	p.sLo, p.sHi = 0, 0
+}
+
+// update absorbs data as 16-byte blocks, zero-padding the last one
+func (p *polyval) update(data []byte) {
+	var block [16]byte
+	for len(data) > 0 {
+		n := copy(block[:], data)
+		clear(block[n:])
+		data = data[n:]
+
+		p.sLo ^= binary.LittleEndian.Uint64(block[:8])
+		p.sHi ^= binary.LittleEndian.Uint64(block[8:])
+		p.sLo, p.sHi = dot(p.sLo, p.sHi, p.hLo, p.hHi)
+	}
+}
+
+func (p *polyval) sum() [16]byte {
+	var out [16]byte
+	binary.LittleEndian.PutUint64(out[:8], p.sLo)
+	binary.LittleEndian.PutUint64(out[8:], p.sHi)
+	return out
+}
+
+// dot returns a * b * x^-128 modulo x^128 + x^127 + x^126 + x^121 + 1
+// Each bit of a adds b, then the running sum is divided by x, so bit i ends up scaled by x^(i-128)
+func dot(aLo, aHi, bLo, bHi uint64) (uint64, uint64) {
+	var rLo, rHi uint64
+	for i := 0; i < 128; i++ {
+		var bit uint64
+		if i < 64 {
+			bit = aLo >> i & 1
+		} else {
+			bit = aHi >> (i - 64) & 1
+		}
+		mask := -bit
+		rLo ^= bLo & mask
+		rHi ^= bHi & mask
+
+		// Divide by x: shift right, folding the constant term back in through the polynomial
+		carry := -(rLo & 1)
+		rLo = rLo>>1 | rHi<<63
+		rHi >>= 1
+		rHi ^= carry & (1<<63 | 1<<62 | 1<<61 | 1<<56)
+	}
+	return rLo, rHi
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+// blocks returns n 16-byte blocks whose first bytes count up from first, as in the RFC 8452 vectors
+func blocks(first byte, n int) string {
+	var b []byte
+	for i := range n {
+		block := make([]byte, 16)
+		block[0] = first + byte(i)
+		b = append(b, block...)
+	}
+	return hex.EncodeToString(b)
+}
+
+// gcmSIVVectors are every test vector of RFC 8452 Appendix C, which defines no AES-192 variant,
+// and the counter overflow cases of Project Wycheproof
+var gcmSIVVectors = []struct {
+	name                      string
+	key, nonce, aad, pt, want string
+}{
+	// C.1 AEAD_AES_128_GCM_SIV
+	{"128/empty", "01000000000000000000000000000000", "030000000000000000000000", "", "", "dc20e2d83f25705bb49e439eca56de25"},
+	{"128/8", "01000000000000000000000000000000", "030000000000000000000000", "", "0100000000000000", "b5d839330ac7b786578782fff6013b815b287c22493a364c"},
+	{"128/12", "01000000000000000000000000000000", "030000000000000000000000", "", "010000000000000000000000", "7323ea61d05932260047d942a4978db357391a0bc4fdec8b0d106639"},
+	{"128/16", "01000000000000000000000000000000", "030000000000000000000000", "", blocks(1, 1), "743f7c8077ab25f8624e2e948579cf77303aaf90f6fe21199c6068577437a0c4"},
+	{"128/32", "01000000000000000000000000000000", "030000000000000000000000", "", blocks(1, 2), "84e07e62ba83a6585417245d7ec413a9fe427d6315c09b57ce45f2e3936a94451a8e45dcd4578c667cd86847bf6155ff"},
+	{"128/48", "01000000000000000000000000000000", "030000000000000000000000", "", blocks(1, 3), "3fd24ce1f5a67b75bf2351f181a475c7b800a5b4d3dcf70106b1eea82fa1d64df42bf7226122fa92e17a40eeaac1201b5e6e311dbf395d35b0fe39c2714388f8"},
+	{"128/64", "01000000000000000000000000000000", "030000000000000000000000", "", blocks(1, 4), "2433668f1058190f6d43e360f4f35cd8e475127cfca7028ea8ab5c20f7ab2af02516a2bdcbc08d521be37ff28c152bba36697f25b4cd169c6590d1dd39566d3f8a263dd317aa88d56bdf3936dba75bb8"},
+	{"128/aad/8", "01000000000000000000000000000000", "030000000000000000000000", "01", "0200000000000000", "1e6daba35669f4273b0a1a2560969cdf790d99759abd1508"},
+	{"128/aad/12", "01000000000000000000000000000000", "030000000000000000000000", "01", "020000000000000000000000", "296c7889fd99f41917f4462008299c5102745aaa3a0c469fad9e075a"},
+	{"128/aad/16", "01000000000000000000000000000000", "030000000000000000000000", "01", blocks(2, 1), "e2b0c5da79a901c1745f700525cb335b8f8936ec039e4e4bb97ebd8c4457441f"},
+	{"128/aad/32", "01000000000000000000000000000000", "030000000000000000000000", "01", blocks(2, 2), "620048ef3c1e73e57e02bb8562c416a319e73e4caac8e96a1ecb2933145a1d71e6af6a7f87287da059a71684ed3498e1"},
+	{"128/aad/48", "01000000000000000000000000000000", "030000000000000000000000", "01", blocks(2, 3), "50c8303ea93925d64090d07bd109dfd9515a5a33431019c17d93465999a8b0053201d723120a8562b838cdff25bf9d1e6a8cc3865f76897c2e4b245cf31c51f2"},
+	{"128/aad/64", "01000000000000000000000000000000", "030000000000000000000000", "01", blocks(2, 4), "2f5c64059db55ee0fb847ed513003746aca4e61c711b5de2e7a77ffd02da42feec601910d3467bb8b36ebbaebce5fba30d36c95f48a3e7980f0e7ac299332a80cdc46ae475563de037001ef84ae21744"},
+	{"128/aad12/4", "01000000000000000000000000000000", "030000000000000000000000", "010000000000000000000000", "02000000", "a8fe3e8707eb1f84fb28f8cb73de8e99e2f48a14"},
+	{"128/aad20/18", "01000000000000000000000000000000", "030000000000000000000000", "0100000000000000000000000000000002000000", "030000000000000000000000000000000400", "44d0aaf6fb2f1f34add5e8064e83e12a2adabff9b2ef00fb47920cc72a0c0f13b9fd"},
+	{"128/aad18/20", "01000000000000000000000000000000", "030000000000000000000000", "010000000000000000000000000000000200", "0300000000000000000000000000000004000000", "6bb0fecf5ded9b77f902c7d5da236a4391dd029724afc9805e976f451e6d87f6fe106514"},
+	{"128/random/0", "e66021d5eb8e4f4066d4adb9c33560e4", "f46e44bb3da0015c94f70887", "", "", "a4194b79071b01a87d65f706e3949578"},
+	{"128/random/3", "36864200e0eaf5284d884a0e77d31646", "bae8e37fc83441b16034566b", "46bb91c3c5", "7a806c", "af60eb711bd85bc1e4d3e0a462e074eea428a8"},
+	{"128/random/6", "aedb64a6c590bc84d1a5e269e4b47801", "afc0577e34699b9e671fdd4f", "fc880c94a95198874296", "bdc66f146545", "bb93a3e34d3cd6a9c45545cfc11f03ad743dba20f966"},
+	{"128/random/9", "d5cc1fd161320b6920ce07787f86743b", "275d1ab32f6d1f0434d8848c", "046787f3ea22c127aaf195d1894728", "1177441f195495860f", "4f37281f7ad12949d01d02fd0cd174c84fc5dae2f60f52fd2b"},
+	{"128/random/12", "b3fed1473c528b8426a582995929a149", "9e9ad8780c8d63d0ab4149c0", "c9882e5386fd9f92ec489c8fde2be2cf97e74e93", "9f572c614b4745914474e7c7", "f54673c5ddf710c745641c8bc1dc2f871fb7561da1286e655e24b7b0"},
+	{"128/random/15", "2d4ed87da44102952ef94b02b805249b", "ac80e6f61455bfac8308a2d4", "2950a70d5a1db2316fd568378da107b52b0da55210cc1c1b0a", "0d8c8451178082355c9e940fea2f58", "c9ff545e07b88a015f05b274540aa183b3449b9f39552de99dc214a1190b0b"},
+	{"128/random/18", "bde3b2f204d1e9f8b06bc47f9745b3d1", "ae06556fb6aa7890bebc18fe", "1860f762ebfbd08284e421702de0de18baa9c9596291b08466f37de21c7f", "6b3db4da3d57aa94842b9803a96e07fb6de7", "6298b296e24e8cc35dce0bed484b7f30d5803e377094f04709f64d7b985310a4db84"},
+	{"128/random/21", "f901cfe8a69615a93fdf7a98cad48179", "6245709fb18853f68d833640", "7576f7028ec6eb5ea7e298342a94d4b202b370ef9768ec6561c4fe6b7e7296fa859c21", "e42a3c02c25b64869e146d7b233987bddfc240871d", "391cc328d484a4f46406181bcd62efd9b3ee197d052d15506c84a9edd65e13e9d24a2a6e70"},
+
+	// C.2 AEAD_AES_256_GCM_SIV
+	{"256/empty", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "", "", "07f5f4169bbf55a8400cd47ea6fd400f"},
+	{"256/8", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "", "0100000000000000", "c2ef328e5c71c83b843122130f7364b761e0b97427e3df28"},
+	{"256/12", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "", "010000000000000000000000", "9aab2aeb3faa0a34aea8e2b18ca50da9ae6559e48fd10f6e5c9ca17e"},
+	{"256/16", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "", blocks(1, 1), "85a01b63025ba19b7fd3ddfc033b3e76c9eac6fa700942702e90862383c6c366"},
+	{"256/32", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "", blocks(1, 2), "4a6a9db4c8c6549201b9edb53006cba821ec9cf850948a7c86c68ac7539d027fe819e63abcd020b006a976397632eb5d"},
+	{"256/48", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "", blocks(1, 3), "c00d121893a9fa603f48ccc1ca3c57ce7499245ea0046db16c53c7c66fe717e39cf6c748837b61f6ee3adcee17534ed5790bc96880a99ba804bd12c0e6a22cc4"},
+	{"256/64", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "", blocks(1, 4), "c2d5160a1f8683834910acdafc41fbb1632d4a353e8b905ec9a5499ac34f96c7e1049eb080883891a4db8caaa1f99dd004d80487540735234e3744512c6f90ce112864c269fc0d9d88c61fa47e39aa08"},
+	{"256/aad/8", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "01", "0200000000000000", "1de22967237a813291213f267e3b452f02d01ae33e4ec854"},
+	{"256/aad/12", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "01", "020000000000000000000000", "163d6f9cc1b346cd453a2e4cc1a4a19ae800941ccdc57cc8413c277f"},
+	{"256/aad/16", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "01", blocks(2, 1), "c91545823cc24f17dbb0e9e807d5ec17b292d28ff61189e8e49f3875ef91aff7"},
+	{"256/aad/32", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "01", blocks(2, 2), "07dad364bfc2b9da89116d7bef6daaaf6f255510aa654f920ac81b94e8bad365aea1bad12702e1965604374aab96dbbc"},
+	{"256/aad/48", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "01", blocks(2, 3), "c67a1f0f567a5198aa1fcc8e3f21314336f7f51ca8b1af61feac35a86416fa47fbca3b5f749cdf564527f2314f42fe2503332742b228c647173616cfd44c54eb"},
+	{"256/aad/64", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "01", blocks(2, 4), "67fd45e126bfb9a79930c43aad2d36967d3f0e4d217c1e551f59727870beefc98cb933a8fce9de887b1e40799988db1fc3f91880ed405b2dd298318858467c895bde0285037c5de81e5b570a049b62a0"},
+	{"256/aad12/4", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "010000000000000000000000", "02000000", "22b3f4cd1835e517741dfddccfa07fa4661b74cf"},
+	{"256/aad20/18", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "0100000000000000000000000000000002000000", "030000000000000000000000000000000400", "462401724b5ce6588d5a54aae5375513a075cfcdf5042112aa29685c912fc2056543"},
+	{"256/aad18/20", "0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "010000000000000000000000000000000200", "0300000000000000000000000000000004000000", "43dd0163cdb48f9fe3212bf61b201976067f342bb879ad976d8242acc188ab59cabfe307"},
+	{"256/random/0", "e66021d5eb8e4f4066d4adb9c33560e4f46e44bb3da0015c94f7088736864200", "e0eaf5284d884a0e77d31646", "", "", "169fbb2fbf389a995f6390af22228a62"},
+	{"256/random/3", "bae8e37fc83441b16034566b7a806c46bb91c3c5aedb64a6c590bc84d1a5e269", "e4b47801afc0577e34699b9e", "4fbdc66f14", "671fdd", "0eaccb93da9bb81333aee0c785b240d319719d"},
+	{"256/random/6", "6545fc880c94a95198874296d5cc1fd161320b6920ce07787f86743b275d1ab3", "2f6d1f0434d8848c1177441f", "6787f3ea22c127aaf195", "195495860f04", "a254dad4f3f96b62b84dc40c84636a5ec12020ec8c2c"},
+	{"256/random/9", "d1894728b3fed1473c528b8426a582995929a1499e9ad8780c8d63d0ab4149c0", "9f572c614b4745914474e7c7", "489c8fde2be2cf97e74e932d4ed87d", "c9882e5386fd9f92ec", "0df9e308678244c44bc0fd3dc6628dfe55ebb0b9fb2295c8c2"},
+	{"256/random/12", "a44102952ef94b02b805249bac80e6f61455bfac8308a2d40d8c845117808235", "5c9e940fea2f582950a70d5a", "0da55210cc1c1b0abde3b2f204d1e9f8b06bc47f", "1db2316fd568378da107b52b", "8dbeb9f7255bf5769dd56692404099c2587f64979f21826706d497d5"},
+	{"256/random/15", "9745b3d1ae06556fb6aa7890bebc18fe6b3db4da3d57aa94842b9803a96e07fb", "6de71860f762ebfbd08284e4", "f37de21c7ff901cfe8a69615a93fdf7a98cad481796245709f", "21702de0de18baa9c9596291b08466", "793576dfa5c0f88729a7ed3c2f1bffb3080d28f6ebb5d3648ce97bd5ba67fd"},
+	{"256/random/18", "b18853f68d833640e42a3c02c25b64869e146d7b233987bddfc240871d7576f7", "028ec6eb5ea7e298342a94d4", "9c2159058b1f0fe91433a5bdc20e214eab7fecef4454a10ef0657df21ac7", "b202b370ef9768ec6561c4fe6b7e7296fa85", "857e16a64915a787637687db4a9519635cdd454fc2a154fea91f8363a39fec7d0a49"},
+	{"256/random/21", "3c535de192eaed3822a2fbbe2ca9dfc88255e14a661b8aa82cc54236093bbc23", "688089e55540db1872504e1c", "734320ccc9d9bbbb19cb81b2af4ecbc3e72834321f7aa0f70b7282b4f33df23f167541", "ced532ce4159b035277d4dfbb7db62968b13cd4eec", "626660c26ea6612fb17ad91e8e767639edd6c9faee9d6c7029675b89eaf4ba1ded1a286594"},
+
+	// C.3 counter wraparound
+	{"256/wrap/32", "0000000000000000000000000000000000000000000000000000000000000000", "000000000000000000000000", "", "000000000000000000000000000000004db923dc793ee6497c76dcc03a98e108", "f3f80f2cf0cb2dd9c5984fcda908456cc537703b5ba70324a6793a7bf218d3eaffffffff000000000000000000000000"},
+	{"256/wrap/24", "0000000000000000000000000000000000000000000000000000000000000000", "000000000000000000000000", "", "eb3640277c7ffd1303c7a542d02d3e4c0000000000000000", "18ce4f0b8cb4d0cac65fea8f79257b20888e53e72299e56dffffffff000000000000000000000000"},
+
+	// Wycheproof tags whose counter blocks overflow the 32-bit counter
+	{"128/overflow/1", "00112233445566778899aabbccddeeff", "010101010101010101010101", "395f4091b410c373073bcdc79e02d3af", "43488548d88e6f774bcd2d52c18fbcc933a4e9a9613ff3edbe959ec59522adc098b3133b8d17b9e9dad631ad33752c95", "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"},
+	{"128/overflow/2", "00112233445566778899aabbccddeeff", "000000000000000000000000", "616b2dff4d665e5f7ab890723dd981b1", "f012c6a7eb0e8af5bc45e015e7680a693dc709b95383f6a94babec1bc36e4be3cf4f55a31a94f11c6c3f90eed99682bc", "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000ffffffffffffffffffffffffffffffff"},
+	{"128/overflow/3", "00112233445566778899aabbccddeeff", "030303030303030303030303", "387a8997605fd04ae8951c4759087864", "71ceee58179d6fb968521e9594dbf98cc0040f6aa38fe873c32a9b122d6cbfd51aa4778b3f4f37be7348690d97e2468b", "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000fefffffffefffffffefffffffeffffff"},
+	{"128/overflow/4", "00112233445566778899aabbccddeeff", "060606060606060606060606", "6783b0d5e9d8a2a7274065797097d1ae", "2e14f9e9a09ea204557367898a80dcad117af3666bea25762b70633a9f3614fbe631ba617c371fd5566d5e613496e69f", "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000ffffff7f00112233445566778899aabb"},
+	{"128/overflow/5", "00112233445566778899aabbccddeeff", "010101010101010101010101", "2933810c146f4f7dd146dd43f35199c6", "27fac75879c9d87cd52a0793137ba792f6f145148158eb538f2081e09cd0315986a7025045ecbb2ca1bb18a17bfcd567", "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000ffffffffffffff7f0011223344556677"},
+	{"256/overflow/1", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "010101010101010101010101", "40c32e00c2fdab59c1a1c573b46b5068", "bdd411814564c4218d224d50591c818855a862a0a519ac0b3d71a2edb12aa71eb81959bcc6b84c45aa424c9aca0b7bdd", "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"},
+	{"256/overflow/2", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "000000000000000000000000", "2cc3a1973e0560f7224a394e52fa8488", "d04846a01f472262e60a1cb4cfcbdcb05c3f819628a3a49395c5dae96c434b2417ce071699afa74a60c32c0bafd9c01a", "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000ffffffffffffffffffffffffffffffff"},
+	{"256/overflow/3", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "010101010101010101010101", "2e34d12622a441b557eeb1d647c6cb73", "79637cee9decf33e3080de3d2c55bd21cd529ba8080b583edb6cfe13cda04bd00debe58b8cd48d6e02a1ecfc4d87923a", "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000fefffffffefffffffefffffffeffffff"},
+	{"256/overflow/4", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "000000000000000000000000", "0814a95481bf915a4097949e3525c7e7", "6492a73880dac7f36743715b0fc7063d3e46a25044310bba5849ed88bfcb54b0adbe3978040bda849906e1aa09d1a8e3", "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000ffffff7f00112233445566778899aabb"},
+	{"256/overflow/5", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "010101010101010101010101", "b691ef42f2ab8d1b4a581bb08394b13a", "7848d9e872f40bca1b82a4e7185fb75193b3496cc1dc2a72b86ed156ab8389e71687ed25eb6485e66561fa8c39853368", "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000ffffffffffffff7f0011223344556677"},
+}
+
+func TestGCMSIVVectors(t *testing.T) {
+	for _, v := range gcmSIVVectors {
+		t.Run(v.name, func(t *testing.T) {
+			aead, err := newGCMSIV(mustHex(t, v.key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			nonce, aad, pt := mustHex(t, v.nonce), mustHex(t, v.aad), mustHex(t, v.pt)
+
+			sealed := aead.Seal(nil, nonce, pt, aad)
+			if got := hex.EncodeToString(sealed); got != v.want {
+				t.Fatalf("Seal = %s, want %s", got, v.want)
+			}
+
+			opened, err := aead.Open(nil, nonce, sealed, aad)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if !bytes.Equal(opened, pt) {
+				t.Fatalf("Open = %x, want %x", opened, pt)
+			}
+		})
+	}
+}
+
+func TestPOLYVAL(t *testing.T) {
+	// RFC 8452 Appendix A
+	var p polyval
+	p.init(mustHex(t, "25629347589242761d31f826ba4b757b"))
+	p.update(mustHex(t, "4f4f95668c83dfb6401762bb2d01a262d1a24ddd2721d006bbe45f20d3c9f362"))
+	if got := p.sum(); hex.EncodeToString(got[:]) != "f7a3b47b846119fae5b7866cf5e5b77e" {
+		t.Fatalf("POLYVAL = %x, want f7a3b47b846119fae5b7866cf5e5b77e", got)
+	}
+}
+
+// The tag must authenticate the AAD even when there is no plaintext
+func TestGCMSIVAADOnly(t *testing.T) {
+	aead, _ := newGCMSIV(mustHex(t, "01000000000000000000000000000000"))
+	nonce := mustHex(t, "030000000000000000000000")
+
+	sealed := aead.Seal(nil, nonce, nil, []byte("tenant-1"))
+	if bytes.Equal(sealed, aead.Seal(nil, nonce, nil, nil)) {
+		t.Fatal("tag does not depend on the AAD")
+	}
+	if _, err := aead.Open(nil, nonce, sealed, []byte("tenant-1")); err != nil {
+		t.Fatalf("Open with the same AAD: %v", err)
+	}
+	if _, err := aead.Open(nil, nonce, sealed, []byte("tenant-2")); err == nil {
+		t.Fatal("Open accepted a different AAD")
+	}
+}
+
+func TestGCMSIVTamper(t *testing.T) {
+	for _, keySize := range []int{16, 32} {
+		aead, _ := newGCMSIV(make([]byte, keySize))
+		nonce := make([]byte, gcmSIVNonceSize)
+		sealed := aead.Seal(nil, nonce, []byte("attack at dawn, attack at dusk"), []byte("aad"))
+
+		for _, i := range []int{0, len(sealed) - gcmSIVTagSize, len(sealed) - 1} {
+			tampered := bytes.Clone(sealed)
+			tampered[i] ^= 0x01
+			if _, err := aead.Open(nil, nonce, tampered, []byte("aad")); err == nil {
+				t.Errorf("AES-%d-GCM-SIV Open accepted a change at byte %d", keySize*8, i)
+			}
+		}
+		if _, err := aead.Open(nil, nonce, sealed[:gcmSIVTagSize-1], nil); err == nil {
+			t.Errorf("AES-%d-GCM-SIV Open accepted a truncated ciphertext", keySize*8)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	cryptomodels "konverter/internal/crypto/models"
+)
+
+// Ciphertexts start with a header recording how they were encrypted, which is authenticated along with the text
+//
+//	"kv" | 1 | algorithm id | KDF id | u16 params length | params | u16 salt length | salt |
+//	u16 ctx info length | ctx info | u16 key id length | key id
+//
+// The header records everything needed to derive the key but the secret
+// Ciphertexts from before the header existed are a bare nonce followed by the sealed text, keyed from the secret
+// by SHA-256, or by HKDF with the decrypt request's salt and ctx_info
+var ciphertextMagic = []byte{'k', 'v'}
+
+// headerVersion is the version byte after the magic; headerless ciphertexts are read as version 0
+const headerVersion = 1
+
+// Key derivation ids that are not password KDFs; they must never change
+const (
+	kdfIDSHA256 = 0
+	kdfIDHKDF   = 0x80
+)
+
+// ciphertextHeader is the decoded header of a ciphertext
+type ciphertextHeader struct {
+	version   byte
+	algorithm aeadAlgorithm
+	// kdfID is kdfIDSHA256, kdfIDHKDF or the id of kdf
+	kdfID  byte
+	kdf    passwordKDF
+	params cryptomodels.KDFParams
+	// salt is the password KDF's random salt or the HKDF salt, and ctxInfo the HKDF info
+	salt    []byte
+	ctxInfo []byte
+	keyID   string
+}
+
+// newCiphertextHeader returns the header for a key derived from the secret by SHA-256, or by HKDF
+// when both salt and ctxInfo are set, as deriveKey does
+func newCiphertextHeader(algorithm aeadAlgorithm, salt, ctxInfo, keyID string) ciphertextHeader {
+	header := ciphertextHeader{version: headerVersion, algorithm: algorithm, kdfID: kdfIDSHA256, keyID: keyID}
+	if salt != "" && ctxInfo != "" {
+		header.kdfID = kdfIDHKDF
+		header.salt = []byte(salt)
+		header.ctxInfo = []byte(ctxInfo)
+	}
+	return header
+}
+
+// usesPasswordKDF reports whether the header's key comes from a password KDF
+func (h ciphertextHeader) usesPasswordKDF() bool {
+	return h.version == headerVersion && h.kdfID != kdfIDSHA256 && h.kdfID != kdfIDHKDF
+}
+
+// encode returns the header bytes
+func (h ciphertextHeader) encode() []byte {
+	var params []byte
+	if h.usesPasswordKDF() {
+		params = h.kdf.encode(h.params)
+	}
+
+	header := append(bytes.Clone(ciphertextMagic), headerVersion, h.algorithm.id, h.kdfID)
+	for _, field := range [][]byte{params, h.salt, h.ctxInfo, []byte(h.keyID)} {
+		header = binary.BigEndian.AppendUint16(header, uint16(len(field)))
+		header = append(header, field...)
+	}
+	return header
+}
+
+// deriveKey derives the key for the header's algorithm as the header records; headerless ciphertexts do not
+// record whether HKDF was used, so their key comes from the secret, salt and ctxInfo of the request
+func (h ciphertextHeader) deriveKey(secret, salt, ctxInfo string) ([]byte, error) {
+	switch {
+	case h.usesPasswordKDF():
+		return h.kdf.deriveKey(secret, h.salt, h.params, h.algorithm.keySize)
+	case h.version == headerVersion:
+		return deriveKey(secret, string(h.salt), string(h.ctxInfo), h.algorithm.keySize)
+	}
+	return deriveKey(secret, salt, ctxInfo, h.algorithm.keySize)
+}
+
+// kdfName names how the key was derived, taking salt and ctxInfo from the request for headerless ciphertexts
+func (h ciphertextHeader) kdfName(salt, ctxInfo string) string {
+	switch {
+	case h.usesPasswordKDF():
+		return h.kdf.name
+	case h.version == headerVersion && h.kdfID == kdfIDHKDF:
+		return "hkdf-sha256"
+	case h.version == headerVersion:
+		return "sha256"
+	case salt != "" && ctxInfo != "":
+		return "hkdf-sha256"
+	}
+	return "sha256"
+}
+
+// hasCiphertextHeader reports whether data starts with the magic bytes and the header version
+// A headerless ciphertext's random nonce matches one time in 2^24, but then almost never parses as a header
+func hasCiphertextHeader(data []byte) bool {
+	return len(data) > len(ciphertextMagic) && bytes.HasPrefix(data, ciphertextMagic) && data[2] == headerVersion
+}
+
+// headerReader reads the fields of a header, remembering the first error
+type headerReader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+// next returns the next n bytes
+func (r *headerReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data)-r.offset < n {
+		r.err = errors.New("ciphertext header is truncated")
+		return nil
+	}
+	field := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return field
+}
+
+// readByte returns the next byte
+func (r *headerReader) readByte() byte {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+// field returns the next field, prefixed by its 16-bit length
+func (r *headerReader) field() []byte {
+	length := r.next(2)
+	if length == nil {
+		return nil
+	}
+	return r.next(int(binary.BigEndian.Uint16(length)))
+}
+
+// parseCiphertextHeader decodes the header data starts with, returning it and its size
+// KDF parameters are checked against the same limits as when encrypting, since the header is not yet authenticated
+func parseCiphertextHeader(data []byte) (ciphertextHeader, int, error) {
+	if !hasCiphertextHeader(data) {
+		return ciphertextHeader{}, 0, errors.New("ciphertext has no header")
+	}
+
+	r := headerReader{data: data, offset: len(ciphertextMagic)}
+	header := ciphertextHeader{version: r.readByte()}
+	algorithmID := r.readByte()
+	if r.err != nil {
+		return ciphertextHeader{}, 0, r.err
+	}
+	algorithm, ok := algorithmByID(algorithmID)
+	if !ok {
+		return ciphertextHeader{}, 0, errors.New("ciphertext header names an unknown algorithm")
+	}
+	header.algorithm = algorithm
+
+	header.kdfID = r.readByte()
+	params := r.field()
+	header.salt = r.field()
+	header.ctxInfo = r.field()
+	header.keyID = string(r.field())
+	if r.err != nil {
+		return ciphertextHeader{}, 0, r.err
+	}
+
+	if header.kdfID == kdfIDSHA256 || header.kdfID == kdfIDHKDF {
+		// HKDF needs both a salt and ctx info, and SHA-256 uses neither
+		hkdf := header.kdfID == kdfIDHKDF
+		if len(params) != 0 || (len(header.salt) != 0) != hkdf || (len(header.ctxInfo) != 0) != hkdf {
+			return ciphertextHeader{}, 0, errors.New("ciphertext header has malformed key derivation fields")
+		}
+		return header, r.offset, nil
+	}
+
+	kdf, ok := kdfByID(header.kdfID)
+	if !ok {
+		return ciphertextHeader{}, 0, errors.New("ciphertext header names an unknown kdf")
+	}
+	if len(params) != kdf.paramsSize || len(header.ctxInfo) != 0 {
+		return ciphertextHeader{}, 0, errors.New("ciphertext header has malformed " + kdf.name + " parameters")
+	}
+	header.kdf = kdf
+	header.params = kdf.decode(params)
+	if err := kdf.check(header.params); err != nil {
+		return ciphertextHeader{}, 0, errors.New("ciphertext header has invalid parameters: " + err.Error())
+	}
+	return header, r.offset, nil
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"encoding/hex"
+	"errors"
+
+	cryptomodels "konverter/internal/crypto/models"
+)
+
+// Inspect describes an encoded ciphertext from its header without decrypting it
+func Inspect(req cryptomodels.InspectRequest) (cryptomodels.InspectResponse, error) {
+	if err := req.Validate(); err != nil {
+		return cryptomodels.InspectResponse{}, err
+	}
+
+	ciphertext, err := decodeBytes(req.Text, req.InputEncoding)
+	if err != nil {
+		return cryptomodels.InspectResponse{}, err
+	}
+
+	// Without a header that parses, the ciphertext is read as a legacy one, as Decrypt does
+	var notes []string
+	algorithm, _ := lookupAlgorithm(defaultAlgorithm)
+	header, size := ciphertextHeader{algorithm: algorithm}, 0
+	if hasCiphertextHeader(ciphertext) {
+		parsed, n, err := parseCiphertextHeader(ciphertext)
+		if err != nil {
+			notes = append(notes, "The ciphertext starts like a header, but "+err.Error()+"; it is read as a ciphertext without a header")
+		} else {
+			header, size = parsed, n
+		}
+	}
+
+	// The nonce and tag sizes depend only on the algorithm, so any key of the right size gives them
+	aead, err := header.algorithm.newAEAD(make([]byte, header.algorithm.keySize))
+	if err != nil {
+		return cryptomodels.InspectResponse{}, errors.New("failed to create " + header.algorithm.name + " cipher: " + err.Error())
+	}
+	body := ciphertext[size:]
+	if len(body) < aead.NonceSize()+aead.Overhead() {
+		return cryptomodels.InspectResponse{}, errors.New("ciphertext too short for " + header.algorithm.name)
+	}
+
+	res := cryptomodels.InspectResponse{
+		Version:       int(header.version),
+		Algorithm:     header.algorithm.name,
+		CtxInfo:       string(header.ctxInfo),
+		KeyID:         header.keyID,
+		HeaderSize:    size,
+		NonceSize:     aead.NonceSize(),
+		Nonce:         hex.EncodeToString(body[:aead.NonceSize()]),
+		TagSize:       aead.Overhead(),
+		PlaintextSize: len(body) - aead.NonceSize() - aead.Overhead(),
+	}
+	if len(header.salt) > 0 {
+		res.Salt = hex.EncodeToString(header.salt)
+	}
+	if header.usesPasswordKDF() {
+		params := header.params
+		res.KDFParams = &params
+	}
+
+	if header.version == 0 {
+		notes = append(notes, "No header: a legacy AES-256-GCM ciphertext, keyed by SHA-256 of the secret or by HKDF with the salt and ctx_info given to decrypt")
+	} else {
+		res.KDF = header.kdfName("", "")
+	}
+	res.Notes = notes
+
+	return res, nil
+}
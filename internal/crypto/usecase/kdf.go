@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	cryptomodels "konverter/internal/crypto/models"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// kdfSaltSize is the size of the random salt generated for each message
+const kdfSaltSize = 16
+
+// Limits on password KDF parameters; they also apply to parameters read from untrusted ciphertext headers,
+// so a crafted ciphertext cannot make decryption use unbounded memory or time
+// The memory limits match the argon2id default, so that anything Encrypt writes can be decrypted
+const (
+	maxArgon2Iterations = 10
+	maxArgon2MemoryKiB  = 64 * 1024
+	maxKDFParallelism   = 16
+	minScryptCostLog2   = 10
+	maxScryptCostLog2   = 20
+	maxScryptBlockSize  = 16
+	maxScryptMemory     = 64 << 20
+	maxPBKDF2Iterations = 10_000_000
+)
+
+// maxConcurrentKDFs bounds the password KDFs running at once, and so the memory they use together
+const maxConcurrentKDFs = 4
+
+// kdfSlots holds a token for each password KDF running
+var kdfSlots = make(chan struct{}, maxConcurrentKDFs)
+
+// passwordKDF is a salted, deliberately slow key derivation for human passwords
+type passwordKDF struct {
+	// id identifies the KDF in ciphertext headers and must never change
+	id   byte
+	name string
+	// defaults fills zero parameters; parameters without a default do not apply to the KDF
+	defaults cryptomodels.KDFParams
+	check    func(p cryptomodels.KDFParams) error
+	// encode and decode convert the parameters to and from their header form of paramsSize bytes
+	paramsSize int
+	encode     func(p cryptomodels.KDFParams) []byte
+	decode     func(b []byte) cryptomodels.KDFParams
+	derive     func(secret, salt []byte, p cryptomodels.KDFParams, keySize int) ([]byte, error)
+}
+
+// passwordKDFs are the supported password KDFs, in the order they are listed in errors
+var passwordKDFs = []passwordKDF{
+	{
+		id:       1,
+		name:     "argon2id",
+		defaults: cryptomodels.KDFParams{Iterations: 3, MemoryKiB: 64 * 1024, Parallelism: 4},
+		check: func(p cryptomodels.KDFParams) error {
+			if p.Iterations < 1 || p.Iterations > maxArgon2Iterations {
+				return fmt.Errorf("argon2id iterations must be between 1 and %d", maxArgon2Iterations)
+			}
+			if p.Parallelism < 1 || p.Parallelism > maxKDFParallelism {
+				return fmt.Errorf("argon2id parallelism must be between 1 and %d", maxKDFParallelism)
+			}
+			if p.MemoryKiB < 8*uint32(p.Parallelism) || p.MemoryKiB > maxArgon2MemoryKiB {
+				return fmt.Errorf("argon2id memory_kib must be between 8 x parallelism (%d) and %d", 8*uint32(p.Parallelism), maxArgon2MemoryKiB)
+			}
+			return nil
+		},
+		paramsSize: 9,
+		encode: func(p cryptomodels.KDFParams) []byte {
+			b := binary.BigEndian.AppendUint32(nil, p.Iterations)
+			b = binary.BigEndian.AppendUint32(b, p.MemoryKiB)
+			return append(b, p.Parallelism)
+		},
+		decode: func(b []byte) cryptomodels.KDFParams {
+			return cryptomodels.KDFParams{
+				Iterations:  binary.BigEndian.Uint32(b[0:4]),
+				MemoryKiB:   binary.BigEndian.Uint32(b[4:8]),
+				Parallelism: b[8],
+			}
+		},
+		derive: func(secret, salt []byte, p cryptomodels.KDFParams, keySize int) ([]byte, error) {
+			return argon2.IDKey(secret, salt, p.Iterations, p.MemoryKiB, p.Parallelism, uint32(keySize)), nil
+		},
+	},
+	{
+		id:       2,
+		name:     "scrypt",
+		defaults: cryptomodels.KDFParams{CostLog2: 15, BlockSize: 8, Parallelism: 1},
+		check: func(p cryptomodels.KDFParams) error {
+			if p.CostLog2 < minScryptCostLog2 || p.CostLog2 > maxScryptCostLog2 {
+				return fmt.Errorf("scrypt cost_log2 must be between %d and %d", minScryptCostLog2, maxScryptCostLog2)
+			}
+			if p.BlockSize < 1 || p.BlockSize > maxScryptBlockSize {
+				return fmt.Errorf("scrypt block_size must be between 1 and %d", maxScryptBlockSize)
+			}
+			if p.Parallelism < 1 || p.Parallelism > maxKDFParallelism {
+				return fmt.Errorf("scrypt parallelism must be between 1 and %d", maxKDFParallelism)
+			}
+			if 128*uint64(p.BlockSize)<<p.CostLog2 > maxScryptMemory {
+				return fmt.Errorf("scrypt memory (128 x block_size x 2^cost_log2) must be at most %d MiB", maxScryptMemory>>20)
+			}
+			return nil
+		},
+		paramsSize: 6,
+		encode: func(p cryptomodels.KDFParams) []byte {
+			b := binary.BigEndian.AppendUint32([]byte{p.CostLog2}, p.BlockSize)
+			return append(b, p.Parallelism)
+		},
+		decode: func(b []byte) cryptomodels.KDFParams {
+			return cryptomodels.KDFParams{CostLog2: b[0], BlockSize: binary.BigEndian.Uint32(b[1:5]), Parallelism: b[5]}
+		},
+		derive: func(secret, salt []byte, p cryptomodels.KDFParams, keySize int) ([]byte, error) {
+			return scrypt.Key(secret, salt, 1<<p.CostLog2, int(p.BlockSize), int(p.Parallelism), keySize)
+		},
+	},
+	{
+		id:       3,
+		name:     "pbkdf2-sha256",
+		defaults: cryptomodels.KDFParams{Iterations: 600_000},
+		check: func(p cryptomodels.KDFParams) error {
+			if p.Iterations < 1 || p.Iterations > maxPBKDF2Iterations {
+				return fmt.Errorf("pbkdf2-sha256 iterations must be between 1 and %d", maxPBKDF2Iterations)
+			}
+			return nil
+		},
+		paramsSize: 4,
+		encode: func(p cryptomodels.KDFParams) []byte {
+			return binary.BigEndian.AppendUint32(nil, p.Iterations)
+		},
+		decode: func(b []byte) cryptomodels.KDFParams {
+			return cryptomodels.KDFParams{Iterations: binary.BigEndian.Uint32(b)}
+		},
+		derive: func(secret, salt []byte, p cryptomodels.KDFParams, keySize int) ([]byte, error) {
+			return pbkdf2.Key(secret, salt, int(p.Iterations), keySize, sha256.New), nil
+		},
+	},
+}
+
+// kdfAliases maps alternative spellings to password KDF names
+var kdfAliases = map[string]string{
+	"argon2": "argon2id",
+	"pbkdf2": "pbkdf2-sha256",
+}
+
+// lookupKDF finds a password KDF by name, case-insensitively and accepting '_' for '-'
+func lookupKDF(name string) (passwordKDF, error) {
+	normalized := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "_", "-")
+	if alias, ok := kdfAliases[normalized]; ok {
+		normalized = alias
+	}
+	for _, kdf := range passwordKDFs {
+		if kdf.name == normalized {
+			return kdf, nil
+		}
+	}
+
+	names := make([]string, 0, len(passwordKDFs))
+	for _, kdf := range passwordKDFs {
+		names = append(names, kdf.name)
+	}
+	return passwordKDF{}, errors.New("unsupported kdf: " + name + ", expected one of " + strings.Join(names, ", "))
+}
+
+// kdfByID finds the password KDF a ciphertext header names
+func kdfByID(id byte) (passwordKDF, bool) {
+	for _, kdf := range passwordKDFs {
+		if kdf.id == id {
+			return kdf, true
+		}
+	}
+	return passwordKDF{}, false
+}
+
+// params fills unset parameters with the KDF's defaults and checks them against its limits
+func (k passwordKDF) params(p cryptomodels.KDFParams) (cryptomodels.KDFParams, error) {
+	fields := []struct {
+		name         string
+		set, applies bool
+	}{
+		{"iterations", p.Iterations != 0, k.defaults.Iterations != 0},
+		{"memory_kib", p.MemoryKiB != 0, k.defaults.MemoryKiB != 0},
+		{"parallelism", p.Parallelism != 0, k.defaults.Parallelism != 0},
+		{"cost_log2", p.CostLog2 != 0, k.defaults.CostLog2 != 0},
+		{"block_size", p.BlockSize != 0, k.defaults.BlockSize != 0},
+	}
+	for _, field := range fields {
+		if field.set && !field.applies {
+			return p, errors.New("kdf_params." + field.name + " does not apply to " + k.name)
+		}
+	}
+
+	if p.Iterations == 0 {
+		p.Iterations = k.defaults.Iterations
+	}
+	if p.MemoryKiB == 0 {
+		p.MemoryKiB = k.defaults.MemoryKiB
+	}
+	if p.Parallelism == 0 {
+		p.Parallelism = k.defaults.Parallelism
+	}
+	if p.CostLog2 == 0 {
+		p.CostLog2 = k.defaults.CostLog2
+	}
+	if p.BlockSize == 0 {
+		p.BlockSize = k.defaults.BlockSize
+	}
+	return p, k.check(p)
+}
+
+// deriveKey derives a key of keySize bytes from the secret with the KDF's salt and parameters,
+// waiting while maxConcurrentKDFs others are running
+func (k passwordKDF) deriveKey(secret string, salt []byte, p cryptomodels.KDFParams, keySize int) ([]byte, error) {
+	kdfSlots <- struct{}{}
+	defer func() { <-kdfSlots }()
+
+	key, err := k.derive([]byte(secret), salt, p, keySize)
+	if err != nil {
+		return nil, errors.New("failed to derive key using " + k.name + ": " + err.Error())
+	}
+	return key, nil
+}
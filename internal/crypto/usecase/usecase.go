@@ -1,11 +1,10 @@
 package usecase
 
 import (
-	"crypto/aes"
+	"bytes"
 	"crypto/cipher"
 	"crypto/rand"
 	"crypto/sha256"
-	"encoding/base64"
 	"errors"
 	"io"
 
@@ -14,8 +13,9 @@ import (
 	"golang.org/x/crypto/hkdf"
 )
 
-// deriveKey derives a 32-byte AES key from the secret using HKDF or SHA-256
-func deriveKey(secret, salt, ctxInfo string) ([]byte, error) {
+// deriveKey derives a key of keySize bytes from the secret using HKDF or SHA-256
+// Shorter keys are prefixes of the 32-byte key, so the same secret gives the same AES-256 key as before
+func deriveKey(secret, salt, ctxInfo string, keySize int) ([]byte, error) {
 	secretBytes := []byte(secret)
 
 	// If both salt and ctxInfo are provided, use HKDF
@@ -23,11 +23,11 @@ func deriveKey(secret, salt, ctxInfo string) ([]byte, error) {
 		saltBytes := []byte(salt)
 		info := []byte(ctxInfo)
 
-		// Use HKDF with SHA-256 to derive a 32-byte key
+		// Use HKDF with SHA-256 to derive the key
 		hash := sha256.New
 		hkdf := hkdf.New(hash, secretBytes, saltBytes, info)
 
-		key := make([]byte, 32) // AES-256 key size
+		key := make([]byte, keySize)
 		if _, err := io.ReadFull(hkdf, key); err != nil {
 			return nil, errors.New("failed to derive key using HKDF: " + err.Error())
 		}
@@ -37,98 +37,228 @@ func deriveKey(secret, salt, ctxInfo string) ([]byte, error) {
 
 	// Otherwise, use SHA-256 hash of the secret
 	hash := sha256.Sum256(secretBytes)
-	return hash[:], nil
+	return hash[:keySize], nil
 }
 
-// Encrypt encrypts the plaintext using AES-GCM
+// newCipher creates the AEAD for algorithm with key
+func newCipher(algorithm aeadAlgorithm, key []byte) (cipher.AEAD, error) {
+	aead, err := algorithm.newAEAD(key)
+	if err != nil {
+		return nil, errors.New("failed to create " + algorithm.name + " cipher: " + err.Error())
+	}
+	return aead, nil
+}
+
+// open decrypts nonce-prefixed sealed data, authenticating additionalData
+func open(aead cipher.AEAD, data, additionalData []byte) ([]byte, error) {
+	// Check if ciphertext is long enough to contain nonce
+	nonceSize := aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	// Extract nonce and ciphertext
+	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
+
+	// Decrypt the ciphertext
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.New("failed to decrypt: " + err.Error())
+	}
+	return plaintext, nil
+}
+
+// additionalData returns the data authenticated along with the text: the header, then the request's aad
+// The header is self-delimiting, so no aad can be mistaken for part of it
+func additionalData(header []byte, aad string) []byte {
+	return append(bytes.Clone(header), aad...)
+}
+
+// openWithHeader decrypts a ciphertext that starts with the parsed header of size bytes, which is authenticated
+// along with the text
+func openWithHeader(ciphertext []byte, header ciphertextHeader, size int, req cryptomodels.DecryptRequest, requested *aeadAlgorithm) ([]byte, error) {
+	if requested != nil && requested.id != header.algorithm.id {
+		return nil, errors.New("ciphertext was encrypted with " + header.algorithm.name + ", not " + requested.name)
+	}
+
+	key, err := header.deriveKey(req.Secret, req.Salt, req.CtxInfo)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newCipher(header.algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, ciphertext[size:], additionalData(ciphertext[:size], req.AAD))
+}
+
+// openHeaderless decrypts a ciphertext from before headers existed, keyed from the secret, salt and ctx_info
+func openHeaderless(ciphertext []byte, req cryptomodels.DecryptRequest, header ciphertextHeader) ([]byte, error) {
+	key, err := header.deriveKey(req.Secret, req.Salt, req.CtxInfo)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newCipher(header.algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, ciphertext, additionalData(nil, req.AAD))
+}
+
+// Encrypt encrypts the plaintext with the requested AEAD algorithm, AES-256-GCM by default
+// The ciphertext is an envelope: a header recording the algorithm, key derivation and key id, which is also
+// authenticated, followed by the nonce and sealed text
 func Encrypt(req cryptomodels.EncryptRequest) (cryptomodels.EncryptResponse, error) {
 	if err := req.Validate(); err != nil {
 		return cryptomodels.EncryptResponse{}, err
 	}
 
-	// Derive the encryption key
-	key, err := deriveKey(req.Secret, req.Salt, req.CtxInfo)
+	name := req.Algorithm
+	if name == "" {
+		name = defaultAlgorithm
+	}
+	algorithm, err := lookupAlgorithm(name)
 	if err != nil {
 		return cryptomodels.EncryptResponse{}, err
 	}
 
-	// Create AES cipher
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return cryptomodels.EncryptResponse{}, errors.New("failed to create AES cipher: " + err.Error())
+	// The header records the key derivation; password KDFs get a random salt per message, stored with their parameters
+	header := newCiphertextHeader(algorithm, req.Salt, req.CtxInfo, req.KeyID)
+	if req.KDF != "" {
+		kdf, err := lookupKDF(req.KDF)
+		if err != nil {
+			return cryptomodels.EncryptResponse{}, err
+		}
+		params, err := kdf.params(req.KDFParams)
+		if err != nil {
+			return cryptomodels.EncryptResponse{}, err
+		}
+		salt := make([]byte, kdfSaltSize)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return cryptomodels.EncryptResponse{}, errors.New("failed to generate salt: " + err.Error())
+		}
+		header.kdfID, header.kdf, header.params, header.salt = kdf.id, kdf, params, salt
 	}
 
-	// Create GCM mode
-	gcm, err := cipher.NewGCM(block)
+	// Derive the encryption key and create the cipher
+	key, err := header.deriveKey(req.Secret, req.Salt, req.CtxInfo)
 	if err != nil {
-		return cryptomodels.EncryptResponse{}, errors.New("failed to create GCM: " + err.Error())
+		return cryptomodels.EncryptResponse{}, err
+	}
+	aead, err := newCipher(algorithm, key)
+	if err != nil {
+		return cryptomodels.EncryptResponse{}, err
 	}
 
-	// Generate random nonce (12 bytes for GCM)
-	nonce := make([]byte, gcm.NonceSize())
+	// Generate random nonce of the algorithm's size
+	nonce := make([]byte, aead.NonceSize())
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return cryptomodels.EncryptResponse{}, errors.New("failed to generate nonce: " + err.Error())
 	}
 
-	// Encrypt the plaintext
-	plaintext := []byte(req.Text)
-	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
+	// Decode the plaintext if it was given as binary
+	inputEncoding := req.InputEncoding
+	if inputEncoding == "" {
+		inputEncoding = "text"
+	}
+	plaintext, err := decodeBytes(req.Text, inputEncoding)
+	if err != nil {
+		return cryptomodels.EncryptResponse{}, err
+	}
 
-	// Encode as Base64
-	encryptedText := base64.StdEncoding.EncodeToString(ciphertext)
+	// Encrypt the plaintext after the header and nonce, binding it to the header and aad
+	encodedHeader := header.encode()
+	ciphertext := aead.Seal(append(encodedHeader, nonce...), nonce, plaintext, additionalData(encodedHeader, req.AAD))
+
+	// Encode as Base64 by default
+	encryptedText, err := encodeBytes(ciphertext, req.OutputEncoding)
+	if err != nil {
+		return cryptomodels.EncryptResponse{}, err
+	}
 
 	return cryptomodels.EncryptResponse{
 		EncryptedText: encryptedText,
+		Algorithm:     algorithm.name,
+		KDF:           header.kdfName(req.Salt, req.CtxInfo),
+		KeyID:         header.keyID,
 	}, nil
 }
 
-// Decrypt decrypts the Base64 encoded ciphertext using AES-GCM
+// Decrypt decrypts the encoded ciphertext, taking the algorithm and key derivation from its header
+// Ciphertexts without a header are decrypted with the requested algorithm, AES-256-GCM by default
 func Decrypt(req cryptomodels.DecryptRequest) (cryptomodels.DecryptResponse, error) {
 	if err := req.Validate(); err != nil {
 		return cryptomodels.DecryptResponse{}, err
 	}
 
-	// Decode Base64 input
-	ciphertext, err := base64.URLEncoding.DecodeString(req.Text)
-	if err != nil {
-		return cryptomodels.DecryptResponse{}, errors.New("failed to decode Base64: " + err.Error())
+	var requested *aeadAlgorithm
+	if req.Algorithm != "" {
+		algorithm, err := lookupAlgorithm(req.Algorithm)
+		if err != nil {
+			return cryptomodels.DecryptResponse{}, err
+		}
+		requested = &algorithm
 	}
 
-	// Derive the decryption key (same as encryption)
-	key, err := deriveKey(req.Secret, req.Salt, req.CtxInfo)
+	// Decode the ciphertext, Base64 in either alphabet by default
+	ciphertext, err := decodeBytes(req.Text, req.InputEncoding)
 	if err != nil {
 		return cryptomodels.DecryptResponse{}, err
 	}
 
-	// Create AES cipher
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return cryptomodels.DecryptResponse{}, errors.New("failed to create AES cipher: " + err.Error())
+	// Encode the plaintext as requested, UTF-8 text by default
+	outputEncoding := req.OutputEncoding
+	if outputEncoding == "" {
+		outputEncoding = "text"
 	}
 
-	// Create GCM mode
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return cryptomodels.DecryptResponse{}, errors.New("failed to create GCM: " + err.Error())
+	// Decrypt with the algorithm and key derivation recorded in the header; once the header parses, its errors
+	// are reported. A header that does not parse is most likely the nonce of a headerless ciphertext that starts
+	// like one, so that is tried instead, reporting the header error if it also fails
+	var headerErr error
+	if hasCiphertextHeader(ciphertext) {
+		header, size, err := parseCiphertextHeader(ciphertext)
+		if err == nil {
+			plaintext, err := openWithHeader(ciphertext, header, size, req, requested)
+			if err != nil {
+				return cryptomodels.DecryptResponse{}, err
+			}
+			decryptedText, err := encodeBytes(plaintext, outputEncoding)
+			if err != nil {
+				return cryptomodels.DecryptResponse{}, err
+			}
+			return cryptomodels.DecryptResponse{
+				DecryptedText: decryptedText,
+				Algorithm:     header.algorithm.name,
+				KDF:           header.kdfName(req.Salt, req.CtxInfo),
+				KeyID:         header.keyID,
+			}, nil
+		}
+		headerErr = err
 	}
 
-	// Check if ciphertext is long enough to contain nonce
-	nonceSize := gcm.NonceSize()
-	if len(ciphertext) < nonceSize {
-		return cryptomodels.DecryptResponse{}, errors.New("ciphertext too short")
+	// Otherwise decrypt without one: headerless ciphertexts are a nonce followed by the sealed text
+	algorithm, _ := lookupAlgorithm(defaultAlgorithm)
+	if requested != nil {
+		algorithm = *requested
+	}
+	legacy := ciphertextHeader{algorithm: algorithm}
+	plaintext, err := openHeaderless(ciphertext, req, legacy)
+	if err != nil {
+		if headerErr != nil {
+			return cryptomodels.DecryptResponse{}, headerErr
+		}
+		return cryptomodels.DecryptResponse{}, err
 	}
 
-	// Extract nonce and ciphertext
-	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
-
-	// Decrypt the ciphertext
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	decryptedText, err := encodeBytes(plaintext, outputEncoding)
 	if err != nil {
-		return cryptomodels.DecryptResponse{}, errors.New("failed to decrypt: " + err.Error())
+		return cryptomodels.DecryptResponse{}, err
 	}
 
 	return cryptomodels.DecryptResponse{
-		DecryptedText: string(plaintext),
+		DecryptedText: decryptedText,
+		Algorithm:     legacy.algorithm.name,
+		KDF:           legacy.kdfName(req.Salt, req.CtxInfo),
 	}, nil
 }
-
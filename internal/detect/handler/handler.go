@@ -0,0 +1,24 @@
+package detect
+
+import (
+	detectmodels "konverter/internal/detect/models"
+	"konverter/internal/detect/usecase"
+	"konverter/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Detect handles input format and encoding detection requests
+func Detect(c *fiber.Ctx) error {
+	req := detectmodels.DetectRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	res, err := usecase.Detect(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
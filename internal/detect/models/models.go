@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	MaxDataSize = 10 * 1024 * 1024 // 10MB in bytes
+)
+
+type DetectRequest struct {
+	// Data is the blob to identify (text, or binary wrapped as base64/hex/byte array)
+	Data string `json:"data"`
+	// Limit is the maximum number of candidates to return (0 means all)
+	Limit int `json:"limit,omitempty"`
+}
+
+func (r *DetectRequest) Validate() error {
+	if strings.TrimSpace(r.Data) == "" {
+		return errors.New("data is required")
+	}
+	if len(r.Data) > MaxDataSize {
+		return errors.New("data size exceeds maximum limit of 10MB")
+	}
+	if r.Limit < 0 {
+		return errors.New("limit cannot be negative")
+	}
+	return nil
+}
+
+type DetectResponse struct {
+	// Length is the length of the input in bytes
+	Length int `json:"length"`
+	// Candidates are the possible formats, ranked by confidence
+	Candidates []Candidate `json:"candidates"`
+}
+
+// Candidate is a single guess of what the input is
+type Candidate struct {
+	// Format is the detected format (e.g., "json", "gzip", "jwt", "unix_timestamp")
+	Format string `json:"format"`
+	// Encoding is the text encoding the format was found inside (e.g., "base64", "hex"), if any
+	Encoding string `json:"encoding,omitempty"`
+	// Confidence is a score between 0 and 1
+	Confidence float64 `json:"confidence"`
+	// Route is the konverter endpoint suggested to call next, if any
+	Route string `json:"route,omitempty"`
+	// Details holds format-specific information (e.g., JWT header, UUID version)
+	Details map[string]any `json:"details,omitempty"`
+}
@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	detectmodels "konverter/internal/detect/models"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// maxCBORDepth limits nesting when checking CBOR well-formedness
+	maxCBORDepth = 64
+	// maxMsgpackDepth limits nesting when checking MessagePack, before the recursive decoder sees it
+	maxMsgpackDepth = 64
+	// cborBreak is the "break" stop code that ends indefinite-length items
+	cborBreak = 0xFF
+)
+
+var (
+	gzipMagic          = []byte{0x1F, 0x8B, 0x08}
+	zstdMagic          = []byte{0x28, 0xB5, 0x2F, 0xFD}
+	avroContainerMagic = []byte("Obj\x01")
+	cborSelfDescribe   = []byte{0xD9, 0xD9, 0xF7}
+)
+
+// detectBinary checks decoded bytes for known binary formats, starting with magic numbers
+func detectBinary(data []byte) []detectmodels.Candidate {
+	candidates := []detectmodels.Candidate{}
+	if len(data) == 0 {
+		return candidates
+	}
+
+	// Magic numbers are unambiguous, so nothing else needs checking
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		return append(candidates, detectmodels.Candidate{Format: "gzip", Confidence: 0.99})
+	case bytes.HasPrefix(data, zstdMagic):
+		return append(candidates, detectmodels.Candidate{Format: "zstd", Confidence: 0.99})
+	case bytes.HasPrefix(data, avroContainerMagic):
+		return append(candidates, detectmodels.Candidate{Format: "avro_container", Confidence: 0.99})
+	}
+
+	if len(data) >= 2 && data[0]&0x0F == 8 && data[0]>>4 <= 7 && (uint16(data[0])<<8|uint16(data[1]))%31 == 0 {
+		candidates = append(candidates, detectmodels.Candidate{Format: "zlib", Confidence: 0.7})
+	}
+
+	if isBSON(data) {
+		candidates = append(candidates, detectmodels.Candidate{
+			Format:     "bson",
+			Confidence: 0.9,
+			Details:    map[string]any{"document_length": len(data)},
+		})
+	}
+
+	if candidate, ok := detectCBOR(data); ok {
+		candidates = append(candidates, candidate)
+	}
+
+	if candidate, ok := detectMsgpack(data); ok {
+		candidates = append(candidates, candidate)
+	}
+
+	if candidate, ok := detectProtobuf(data); ok {
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates
+}
+
+// isBSON checks the BSON document framing: int32 total length, a known element type and a trailing NUL
+func isBSON(data []byte) bool {
+	if len(data) < 5 || int(binary.LittleEndian.Uint32(data)) != len(data) || data[len(data)-1] != 0 {
+		return false
+	}
+	// An empty document is just the length and the terminator
+	if len(data) == 5 {
+		return true
+	}
+	elementType := data[4]
+	return (elementType >= 0x01 && elementType <= 0x13) || elementType == 0x7F || elementType == 0xFF
+}
+
+// detectCBOR reports CBOR when the whole input is exactly one well-formed data item
+func detectCBOR(data []byte) (detectmodels.Candidate, bool) {
+	if len(data) < 2 {
+		return detectmodels.Candidate{}, false
+	}
+	n, err := cborItemLength(data, 0)
+	if err != nil || n != len(data) {
+		return detectmodels.Candidate{}, false
+	}
+
+	candidate := detectmodels.Candidate{Format: "cbor", Confidence: 0.2}
+	switch major := data[0] >> 5; {
+	case bytes.HasPrefix(data, cborSelfDescribe):
+		candidate.Confidence = 0.99
+	case major == 4 || major == 5:
+		candidate.Confidence = 0.6
+	}
+	return candidate, true
+}
+
+// cborItemLength returns the encoded length of the CBOR data item at the start of data
+func cborItemLength(data []byte, depth int) (int, error) {
+	if depth > maxCBORDepth {
+		return 0, errors.New("cbor nesting too deep")
+	}
+	if len(data) == 0 {
+		return 0, errors.New("unexpected end of cbor data")
+	}
+
+	major := data[0] >> 5
+	info := data[0] & 0x1F
+	offset := 1
+
+	var argument uint64
+	switch {
+	case info < 24:
+		argument = uint64(info)
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(data) < offset+size {
+			return 0, errors.New("unexpected end of cbor data")
+		}
+		for _, b := range data[offset : offset+size] {
+			argument = argument<<8 | uint64(b)
+		}
+		offset += size
+	case info == 31:
+		// Indefinite length: only valid for strings, arrays and maps
+		if major < 2 || major > 5 {
+			return 0, errors.New("invalid cbor indefinite length")
+		}
+		for {
+			if offset >= len(data) {
+				return 0, errors.New("unterminated cbor indefinite item")
+			}
+			if data[offset] == cborBreak {
+				return offset + 1, nil
+			}
+			n, err := cborItemLength(data[offset:], depth+1)
+			if err != nil {
+				return 0, err
+			}
+			offset += n
+		}
+	default:
+		return 0, errors.New("reserved cbor additional information")
+	}
+
+	switch major {
+	case 0, 1, 7:
+		return offset, nil
+	case 2, 3:
+		if uint64(len(data)-offset) < argument {
+			return 0, errors.New("cbor string exceeds data")
+		}
+		return offset + int(argument), nil
+	case 4, 5:
+		items := argument
+		if major == 5 {
+			items *= 2
+		}
+		// Each item needs at least one byte, which bounds hostile lengths
+		if items > uint64(len(data)-offset) {
+			return 0, errors.New("cbor container exceeds data")
+		}
+		for i := uint64(0); i < items; i++ {
+			n, err := cborItemLength(data[offset:], depth+1)
+			if err != nil {
+				return 0, err
+			}
+			offset += n
+		}
+		return offset, nil
+	default:
+		// Tag: followed by exactly one data item
+		n, err := cborItemLength(data[offset:], depth+1)
+		if err != nil {
+			return 0, err
+		}
+		return offset + n, nil
+	}
+}
+
+// detectMsgpack reports MessagePack when the whole input decodes as exactly one value
+func detectMsgpack(data []byte) (detectmodels.Candidate, bool) {
+	if len(data) < 2 {
+		return detectmodels.Candidate{}, false
+	}
+	if n, err := msgpackItemLength(data, 0); err != nil || n != len(data) {
+		return detectmodels.Candidate{}, false
+	}
+
+	reader := bytes.NewReader(data)
+	var value any
+	if err := msgpack.NewDecoder(reader).Decode(&value); err != nil || reader.Len() != 0 {
+		return detectmodels.Candidate{}, false
+	}
+
+	candidate := detectmodels.Candidate{Format: "msgpack", Confidence: 0.25}
+	first := data[0]
+	// fixmap, fixarray, array16/32 and map16/32 are the usual top-level values
+	if (first >= 0x80 && first <= 0x9F) || (first >= 0xDC && first <= 0xDF) {
+		candidate.Confidence = 0.65
+	}
+	return candidate, true
+}
+
+// msgpackItemLength returns the encoded length of the MessagePack value at the start of data
+func msgpackItemLength(data []byte, depth int) (int, error) {
+	if depth > maxMsgpackDepth {
+		return 0, errors.New("msgpack nesting too deep")
+	}
+	if len(data) == 0 {
+		return 0, errors.New("unexpected end of msgpack data")
+	}
+
+	// length reads the big-endian length of size bytes after the type byte
+	length := func(size int) (uint64, error) {
+		if len(data) < 1+size {
+			return 0, errors.New("unexpected end of msgpack data")
+		}
+		var n uint64
+		for _, b := range data[1 : 1+size] {
+			n = n<<8 | uint64(b)
+		}
+		return n, nil
+	}
+
+	first := data[0]
+	var offset int
+	var payload, items uint64
+	switch {
+	case first <= 0x7F || first >= 0xE0 || first == 0xC0 || first == 0xC2 || first == 0xC3:
+		// Fixints, nil and booleans
+		return 1, nil
+	case first <= 0x8F:
+		offset, items = 1, 2*uint64(first&0x0F)
+	case first <= 0x9F:
+		offset, items = 1, uint64(first&0x0F)
+	case first <= 0xBF:
+		offset, payload = 1, uint64(first&0x1F)
+	case first == 0xC1:
+		return 0, errors.New("reserved msgpack type")
+	case first >= 0xC4 && first <= 0xC6, first >= 0xD9 && first <= 0xDB:
+		// bin and str with a 1, 2 or 4 byte length
+		size := 1 << ((first - 0xC4) % 3)
+		if first >= 0xD9 {
+			size = 1 << (first - 0xD9)
+		}
+		n, err := length(size)
+		if err != nil {
+			return 0, err
+		}
+		offset, payload = 1+size, n
+	case first >= 0xC7 && first <= 0xC9:
+		// ext with a 1, 2 or 4 byte length, then a type byte
+		size := 1 << (first - 0xC7)
+		n, err := length(size)
+		if err != nil {
+			return 0, err
+		}
+		offset, payload = 1+size+1, n
+	case first == 0xCA || first == 0xCB:
+		offset, payload = 1, uint64(4)<<(first-0xCA)
+	case first >= 0xCC && first <= 0xCF:
+		offset, payload = 1, uint64(1)<<(first-0xCC)
+	case first >= 0xD0 && first <= 0xD3:
+		offset, payload = 1, uint64(1)<<(first-0xD0)
+	case first >= 0xD4 && first <= 0xD8:
+		// fixext: a type byte and 1 to 16 bytes
+		offset, payload = 2, uint64(1)<<(first-0xD4)
+	default:
+		// array16/32 and map16/32
+		size := 2 << ((first - 0xDC) % 2)
+		n, err := length(size)
+		if err != nil {
+			return 0, err
+		}
+		offset, items = 1+size, n
+		if first >= 0xDE {
+			items *= 2
+		}
+	}
+
+	if offset > len(data) || payload > uint64(len(data)-offset) {
+		return 0, errors.New("msgpack value exceeds data")
+	}
+	offset += int(payload)
+	// Each item needs at least one byte, which bounds hostile lengths
+	if items > uint64(len(data)-offset) {
+		return 0, errors.New("msgpack container exceeds data")
+	}
+	for i := uint64(0); i < items; i++ {
+		n, err := msgpackItemLength(data[offset:], depth+1)
+		if err != nil {
+			return 0, err
+		}
+		offset += n
+	}
+	return offset, nil
+}
+
+// detectProtobuf reports protobuf when the whole input parses as wire-format fields
+func detectProtobuf(data []byte) (detectmodels.Candidate, bool) {
+	fields := 0
+	maxField := protowire.Number(0)
+
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeField(data)
+		if n < 0 || typ == protowire.StartGroupType || typ == protowire.EndGroupType {
+			return detectmodels.Candidate{}, false
+		}
+		fields++
+		maxField = max(maxField, num)
+		data = data[n:]
+	}
+	if fields == 0 {
+		return detectmodels.Candidate{}, false
+	}
+
+	candidate := detectmodels.Candidate{
+		Format:     "protobuf",
+		Confidence: 0.3,
+		Details:    map[string]any{"fields": fields},
+	}
+	// Hand-written schemas rarely use large field numbers
+	if maxField <= 32 {
+		candidate.Confidence = 0.5
+	}
+	return candidate, true
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	detectmodels "konverter/internal/detect/models"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Deeply nested MessagePack must be rejected before the recursive decoder overflows the stack
+func TestDetectDeepMsgpack(t *testing.T) {
+	data := base64.StdEncoding.EncodeToString(append(bytes.Repeat([]byte{0x91}, 2_500_000), 0x00))
+	if _, err := Detect(detectmodels.DetectRequest{Data: data}); err != nil {
+		t.Fatalf("Detect returned error: %v", err)
+	}
+}
+
+func TestMsgpackItemLength(t *testing.T) {
+	values := []any{
+		nil, true, 5, -3, 200, -200, 70000, 1 << 40, 1.5, float32(2.5),
+		"hi", string(bytes.Repeat([]byte("x"), 40)), string(bytes.Repeat([]byte("x"), 300)),
+		[]byte{1, 2, 3}, []any{1, "a", []any{nil}}, make([]any, 20),
+		map[string]any{"a": 1, "b": map[string]any{"c": []any{1.25}}},
+	}
+	for _, value := range values {
+		data, err := msgpack.Marshal(value)
+		if err != nil {
+			t.Fatalf("Marshal(%v): %v", value, err)
+		}
+		if n, err := msgpackItemLength(data, 0); err != nil || n != len(data) {
+			t.Errorf("msgpackItemLength(%x) = %d, %v, want %d", data, n, err, len(data))
+		}
+		if _, err := msgpackItemLength(data[:len(data)-1], 0); err == nil {
+			t.Errorf("msgpackItemLength(%x) accepted truncated data", data[:len(data)-1])
+		}
+	}
+}
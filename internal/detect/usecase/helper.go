@@ -0,0 +1,372 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	detectmodels "konverter/internal/detect/models"
+	encodingusecase "konverter/internal/encoding/usecase"
+	timestampusecase "konverter/internal/timestamp/usecase"
+
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	uuidPattern      = regexp.MustCompile(`^(?i)(urn:uuid:)?\{?([0-9a-f]{8})-([0-9a-f]{4})-([0-9a-f]{4})-([0-9a-f]{4})-([0-9a-f]{12})\}?$`)
+	jwtPattern       = regexp.MustCompile(`^[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*(\.[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*)?$`)
+	integerPattern   = regexp.MustCompile(`^-?[0-9]{1,19}$`)
+	byteArrayPattern = regexp.MustCompile(`^\[\s*[0-9]{1,3}(\s*,?\s*[0-9]{1,3})*\s*\]$`)
+	base64Pattern    = regexp.MustCompile(`^[A-Za-z0-9+/]+={0,2}$`)
+	base64URLPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+={0,2}$`)
+)
+
+// detectText runs every detector that works on the input as text; the detectors expect non-empty input
+func detectText(s string) []detectmodels.Candidate {
+	candidates := []detectmodels.Candidate{}
+	if s == "" {
+		return candidates
+	}
+	detectors := []func(string) (detectmodels.Candidate, bool){
+		detectJSON,
+		detectNDJSON,
+		detectEscapedJSON,
+		detectXML,
+		detectYAML,
+		detectJWT,
+		detectUUID,
+		detectUnixTimestamp,
+	}
+	for _, detector := range detectors {
+		if candidate, ok := detector(s); ok {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// detectJSON reports a single JSON value; objects and arrays are far more likely than scalars
+func detectJSON(s string) (detectmodels.Candidate, bool) {
+	if !jsoniter.Valid([]byte(s)) {
+		return detectmodels.Candidate{}, false
+	}
+	candidate := detectmodels.Candidate{Format: "json", Confidence: 0.3}
+	switch s[0] {
+	case '{':
+		candidate.Confidence = 0.95
+		candidate.Details = map[string]any{"type": "object"}
+	case '[':
+		candidate.Confidence = 0.95
+		candidate.Details = map[string]any{"type": "array"}
+	}
+	return candidate, true
+}
+
+// detectNDJSON reports newline-delimited JSON: several lines that are each a JSON object or array
+func detectNDJSON(s string) (detectmodels.Candidate, bool) {
+	lines := 0
+	for line := range strings.SplitSeq(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if (line[0] != '{' && line[0] != '[') || !jsoniter.Valid([]byte(line)) {
+			return detectmodels.Candidate{}, false
+		}
+		lines++
+	}
+	if lines < 2 {
+		return detectmodels.Candidate{}, false
+	}
+	return detectmodels.Candidate{
+		Format:     "ndjson",
+		Confidence: 0.9,
+		Details:    map[string]any{"lines": lines},
+	}, true
+}
+
+// detectEscapedJSON reports JSON text that has been escaped into a string literal (e.g., {\"a\":1})
+func detectEscapedJSON(s string) (detectmodels.Candidate, bool) {
+	if !strings.Contains(s, `\"`) {
+		return detectmodels.Candidate{}, false
+	}
+	unquoted, err := strconv.Unquote(`"` + s + `"`)
+	if err != nil || unquoted == "" || (unquoted[0] != '{' && unquoted[0] != '[') || !jsoniter.Valid([]byte(unquoted)) {
+		return detectmodels.Candidate{}, false
+	}
+	return detectmodels.Candidate{Format: "escaped_json", Confidence: 0.85}, true
+}
+
+// detectXML reports well-formed XML with at least one element
+func detectXML(s string) (detectmodels.Candidate, bool) {
+	if s[0] != '<' {
+		return detectmodels.Candidate{}, false
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(s))
+	decoder.Strict = true
+	elements := 0
+	var root string
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return detectmodels.Candidate{}, false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			if elements == 0 {
+				root = start.Name.Local
+			}
+			elements++
+		}
+	}
+	if elements == 0 {
+		return detectmodels.Candidate{}, false
+	}
+
+	candidate := detectmodels.Candidate{
+		Format:     "xml",
+		Confidence: 0.9,
+		Details:    map[string]any{"root": root, "elements": elements},
+	}
+	if strings.HasPrefix(s, "<?xml") {
+		candidate.Confidence = 0.97
+	}
+	return candidate, true
+}
+
+// detectYAML reports YAML mappings or sequences that are not also JSON
+func detectYAML(s string) (detectmodels.Candidate, bool) {
+	if s[0] == '{' || s[0] == '[' || (!strings.Contains(s, ": ") && !strings.Contains(s, ":\n") && !strings.HasPrefix(s, "- ")) {
+		return detectmodels.Candidate{}, false
+	}
+
+	var value any
+	if err := yaml.Unmarshal([]byte(s), &value); err != nil {
+		return detectmodels.Candidate{}, false
+	}
+	candidate := detectmodels.Candidate{Format: "yaml", Confidence: 0.6}
+	switch value.(type) {
+	case map[string]any:
+		candidate.Details = map[string]any{"type": "mapping"}
+	case []any:
+		candidate.Details = map[string]any{"type": "sequence"}
+	default:
+		return detectmodels.Candidate{}, false
+	}
+	if strings.HasPrefix(s, "---") {
+		candidate.Confidence = 0.75
+	}
+	return candidate, true
+}
+
+// detectJWT reports a JWS (3 segments) or JWE (5 segments) compact token with a JSON header
+func detectJWT(s string) (detectmodels.Candidate, bool) {
+	if !jwtPattern.MatchString(s) {
+		return detectmodels.Candidate{}, false
+	}
+	segments := strings.Split(s, ".")
+
+	var header map[string]any
+	headerBytes, err := encodingusecase.Decode("base64url-raw", segments[0])
+	if err != nil || jsoniter.Unmarshal(headerBytes, &header) != nil {
+		return detectmodels.Candidate{}, false
+	}
+	if _, ok := header["alg"]; !ok {
+		return detectmodels.Candidate{}, false
+	}
+
+	if len(segments) == 5 {
+		return detectmodels.Candidate{
+			Format:     "jwe",
+			Confidence: 0.95,
+			Details:    map[string]any{"header": header},
+		}, true
+	}
+
+	details := map[string]any{"header": header}
+	var payload any
+	payloadBytes, err := encodingusecase.Decode("base64url-raw", segments[1])
+	if err == nil && jsoniter.Unmarshal(payloadBytes, &payload) == nil {
+		details["payload"] = payload
+	}
+	return detectmodels.Candidate{Format: "jwt", Confidence: 0.98, Details: details}, true
+}
+
+// detectUUID reports a hyphenated UUID, optionally in braces or as a URN
+func detectUUID(s string) (detectmodels.Candidate, bool) {
+	match := uuidPattern.FindStringSubmatch(s)
+	if match == nil {
+		return detectmodels.Candidate{}, false
+	}
+
+	version, _ := strconv.ParseUint(match[4][:1], 16, 8)
+	variantNibble, _ := strconv.ParseUint(match[5][:1], 16, 8)
+	var variant string
+	switch {
+	case variantNibble < 0x8:
+		variant = "ncs"
+	case variantNibble < 0xC:
+		variant = "rfc4122"
+	case variantNibble < 0xE:
+		variant = "microsoft"
+	default:
+		variant = "future"
+	}
+
+	return detectmodels.Candidate{
+		Format:     "uuid",
+		Confidence: 0.99,
+		Details:    map[string]any{"version": version, "variant": variant},
+	}, true
+}
+
+// detectUnixTimestamp reports integers whose detected unit places them at a plausible date
+func detectUnixTimestamp(s string) (detectmodels.Candidate, bool) {
+	if !integerPattern.MatchString(s) {
+		return detectmodels.Candidate{}, false
+	}
+	value, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || value <= 0 {
+		return detectmodels.Candidate{}, false
+	}
+
+	unit := timestampusecase.DetectUnit(value)
+	var t time.Time
+	switch unit {
+	case "seconds":
+		t = time.Unix(value, 0)
+	case "milliseconds":
+		t = time.UnixMilli(value)
+	case "microseconds":
+		t = time.UnixMicro(value)
+	default:
+		t = time.Unix(0, value)
+	}
+
+	// Timestamps seen in practice cluster around the present
+	var confidence float64
+	switch year := t.UTC().Year(); {
+	case year >= 2000 && year <= 2100:
+		confidence = 0.8
+	case year >= 1970 && year <= 2262:
+		confidence = 0.3
+	default:
+		return detectmodels.Candidate{}, false
+	}
+
+	return detectmodels.Candidate{
+		Format:     "unix_timestamp",
+		Confidence: confidence,
+		Details:    map[string]any{"unit": unit, "gmt": t.UTC().Format(time.RFC3339)},
+	}, true
+}
+
+// wrapper is a text encoding that may carry binary or nested text content
+type wrapper struct {
+	name   string
+	decode func(s string) ([]byte, float64, bool)
+}
+
+// wrappers are tried in order; each returns the decoded bytes and a base confidence
+var wrappers = []wrapper{
+	{name: "byte_array", decode: decodeByteArray},
+	{name: "hex", decode: decodeHex},
+	{name: "base64", decode: decodeBase64},
+	{name: "base64url", decode: decodeBase64URL},
+}
+
+// decodeByteArray decodes "[123 111 100]" style byte arrays
+func decodeByteArray(s string) ([]byte, float64, bool) {
+	if !byteArrayPattern.MatchString(s) {
+		return nil, 0, false
+	}
+	parts := strings.Fields(strings.ReplaceAll(strings.Trim(s, "[]"), ",", " "))
+	data := make([]byte, len(parts))
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value > 255 {
+			return nil, 0, false
+		}
+		data[i] = byte(value)
+	}
+	return data, 0.9, true
+}
+
+// decodeHex decodes hex strings; all-digit strings are more likely to be numbers
+func decodeHex(s string) ([]byte, float64, bool) {
+	data, err := encodingusecase.Decode("hex", s)
+	if err != nil || len(data) == 0 {
+		return nil, 0, false
+	}
+
+	confidence := 0.5
+	if strings.IndexFunc(s, unicode.IsLetter) < 0 {
+		confidence = 0.2
+	} else if len(data) >= 8 {
+		confidence = 0.7
+	}
+	return data, confidence, true
+}
+
+// decodeBase64 decodes standard-alphabet base64, with or without padding
+func decodeBase64(s string) ([]byte, float64, bool) {
+	return decodeBase64Variant(stripLineBreaks(s), base64Pattern, "base64-raw")
+}
+
+// decodeBase64URL decodes URL-safe base64 when it uses URL-only characters
+func decodeBase64URL(s string) ([]byte, float64, bool) {
+	s = stripLineBreaks(s)
+	if !strings.ContainsAny(s, "-_") {
+		return nil, 0, false
+	}
+	return decodeBase64Variant(s, base64URLPattern, "base64url-raw")
+}
+
+// decodeBase64Variant scores base64 by its shape: padding, length and a mix of character classes
+func decodeBase64Variant(s string, pattern *regexp.Regexp, codec string) ([]byte, float64, bool) {
+	if len(s) < 4 || !pattern.MatchString(s) {
+		return nil, 0, false
+	}
+	data, err := encodingusecase.Decode(codec, s)
+	if err != nil {
+		return nil, 0, false
+	}
+
+	confidence := 0.3
+	hasUpper := strings.IndexFunc(s, unicode.IsUpper) >= 0
+	hasLower := strings.IndexFunc(s, unicode.IsLower) >= 0
+	hasDigit := strings.IndexFunc(s, unicode.IsDigit) >= 0
+	if hasUpper && hasLower && hasDigit {
+		confidence = 0.5
+	}
+	if len(s)%4 == 0 && len(s) >= 8 {
+		confidence += 0.1
+	}
+	return data, confidence, true
+}
+
+// isPrintableText reports whether data is valid UTF-8 made of printable characters and whitespace
+func isPrintableText(data []byte) bool {
+	if len(data) == 0 || !utf8.Valid(data) {
+		return false
+	}
+	return bytes.IndexFunc(data, func(r rune) bool {
+		return !unicode.IsPrint(r) && !unicode.IsSpace(r)
+	}) < 0
+}
+
+// stripLineBreaks removes line breaks, so wrapped base64 can be decoded while spaced words are not
+func stripLineBreaks(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"slices"
+	"strings"
+
+	detectmodels "konverter/internal/detect/models"
+)
+
+// nestedConfidence scales the confidence of formats found inside a wrapper encoding
+const nestedConfidence = 0.95
+
+// Detect guesses what the input blob is and returns ranked candidates with suggested routes
+func Detect(req detectmodels.DetectRequest) (detectmodels.DetectResponse, error) {
+	if err := req.Validate(); err != nil {
+		return detectmodels.DetectResponse{}, err
+	}
+
+	input := strings.TrimSpace(req.Data)
+	candidates := detectText(input)
+
+	// Try each wrapper encoding and look at what it decodes to
+	for _, w := range wrappers {
+		data, confidence, ok := w.decode(input)
+		if !ok {
+			continue
+		}
+
+		nested := detectBinary(data)
+		// Whitespace-only content has nothing to detect
+		if text := strings.TrimSpace(string(data)); text != "" && isPrintableText(data) {
+			nested = append(nested, detectText(text)...)
+			nested = append(nested, detectmodels.Candidate{Format: "text", Confidence: 0.5})
+		}
+
+		// Recognizable content is strong evidence that the wrapper guess is right
+		for _, candidate := range nested {
+			confidence = max(confidence, candidate.Confidence*nestedConfidence)
+		}
+		candidates = append(candidates, detectmodels.Candidate{
+			Format:     w.name,
+			Confidence: confidence,
+			Details:    map[string]any{"decoded_length": len(data)},
+		})
+
+		for _, candidate := range nested {
+			candidate.Encoding = w.name
+			candidate.Confidence = min(candidate.Confidence, confidence) * nestedConfidence
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	// Plain text is the fallback guess for anything readable
+	if isPrintableText([]byte(input)) {
+		candidates = append(candidates, detectmodels.Candidate{Format: "text", Confidence: 0.35})
+	}
+
+	for i := range candidates {
+		candidates[i].Confidence = roundConfidence(candidates[i].Confidence)
+		candidates[i].Route = suggestRoute(candidates[i].Format, candidates[i].Encoding)
+	}
+
+	slices.SortStableFunc(candidates, func(a, b detectmodels.Candidate) int {
+		switch {
+		case a.Confidence > b.Confidence:
+			return -1
+		case a.Confidence < b.Confidence:
+			return 1
+		default:
+			return 0
+		}
+	})
+	if req.Limit > 0 && len(candidates) > req.Limit {
+		candidates = candidates[:req.Limit]
+	}
+
+	return detectmodels.DetectResponse{
+		Length:     len(req.Data),
+		Candidates: candidates,
+	}, nil
+}
+
+// suggestRoute returns the konverter endpoint that can take the candidate as-is, if any
+func suggestRoute(format, encoding string) string {
+	switch format {
+	case "msgpack":
+		if encoding == "base64" || encoding == "byte_array" {
+			return "/api/v1/msgpack/decode"
+		}
+	case "protobuf":
+		if encoding == "base64" || encoding == "base64url" || encoding == "hex" {
+			return "/api/v1/protobuf/decode"
+		}
+	case "gzip", "zstd", "zlib":
+		if encoding == "base64" || encoding == "base64url" || encoding == "hex" {
+			return "/api/v1/compress/decompress"
+		}
+	case "avro_container":
+		if encoding == "base64" || encoding == "base64url" || encoding == "hex" {
+			return "/api/v1/avro/container/decode"
+		}
+	case "byte_array", "hex", "base64", "base64url":
+		return "/api/v1/encoding/convert"
+	}
+
+	// Nested text formats need their wrapper removed before calling the format route
+	if encoding != "" {
+		return "/api/v1/encoding/convert"
+	}
+
+	switch format {
+	case "json":
+		return "/api/v1/json/format"
+	case "escaped_json":
+		return "/api/v1/json/unescape"
+	case "unix_timestamp":
+		return "/api/v1/timestamp/convert/humanize"
+	case "uuid":
+		return "/api/v1/timestamp/decode/id"
+	}
+	return ""
+}
+
+// roundConfidence keeps scores to two decimal places for readability
+func roundConfidence(confidence float64) float64 {
+	return float64(int(confidence*100+0.5)) / 100
+}
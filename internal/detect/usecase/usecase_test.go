@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"testing"
+
+	detectmodels "konverter/internal/detect/models"
+)
+
+// Wrappers that decode to whitespace only leave no nested text to detect
+func TestDetectWhitespaceOnlyWrappers(t *testing.T) {
+	inputs := []string{
+		"ICAgICAg",   // base64 of six spaces
+		"20202020",   // hex of four spaces
+		"[32 32 32]", // byte array of three spaces
+	}
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			if _, err := Detect(detectmodels.DetectRequest{Data: input}); err != nil {
+				t.Fatalf("Detect(%q) returned error: %v", input, err)
+			}
+		})
+	}
+}
+
+func TestDetectTextEmpty(t *testing.T) {
+	if candidates := detectText(""); len(candidates) != 0 {
+		t.Fatalf("detectText(\"\") = %v, want no candidates", candidates)
+	}
+}
@@ -0,0 +1,29 @@
+package encoding
+
+import (
+	encodingmodels "konverter/internal/encoding/models"
+	"konverter/internal/encoding/usecase"
+	"konverter/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Convert handles conversion requests between two binary-to-text encodings
+func Convert(c *fiber.Ctx) error {
+	req := encodingmodels.ConvertRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	res, err := usecase.Convert(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
+
+// Formats lists the supported encodings
+func Formats(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: usecase.Formats()})
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	MaxDataSize   = 10 * 1024 * 1024 // 10MB in bytes
+	MaxBase58Size = 4 * 1024         // 4KB of base58 text, since base58 takes quadratic time and is meant for short identifiers
+)
+
+type ConvertRequest struct {
+	// From is the encoding of Data (e.g., "text", "base64", "hex", "base58")
+	From string `json:"from"`
+	// To is the encoding of the output (e.g., "text", "base64url-raw", "z85")
+	To string `json:"to"`
+	// Data is the input encoded as From
+	Data string `json:"data"`
+}
+
+func (r *ConvertRequest) Validate() error {
+	if r.From == "" {
+		return errors.New("from is required")
+	}
+	if r.To == "" {
+		return errors.New("to is required")
+	}
+	if r.Data == "" {
+		return errors.New("data is required")
+	}
+	if len(r.Data) > MaxDataSize {
+		return errors.New("data size exceeds maximum limit of 10MB")
+	}
+	if strings.HasPrefix(strings.ToLower(r.From), "base58") && len(r.Data) > MaxBase58Size {
+		return errors.New("base58 data size exceeds maximum limit of 4KB, as base58 is meant for short identifiers")
+	}
+	return nil
+}
+
+type ConvertResponse struct {
+	// From is the input encoding
+	From string `json:"from"`
+	// To is the output encoding
+	To string `json:"to"`
+	// Output is the data encoded as To
+	Output string `json:"output"`
+	// ByteLength is the length of the decoded binary data
+	ByteLength int `json:"byte_length"`
+}
+
+// Format describes a supported encoding
+type Format struct {
+	// Name is the identifier used in from/to
+	Name string `json:"name"`
+	// Description is a short human-readable description
+	Description string `json:"description"`
+}
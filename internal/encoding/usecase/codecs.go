@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	encodingmodels "konverter/internal/encoding/models"
+)
+
+const (
+	base58BitcoinAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	base58FlickrAlphabet  = "123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+	// base85Alphabet is the RFC 1924 alphabet, also used by git and Python's b85encode
+	base85Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$%&()*+-;<=>?@^_`{|}~"
+	// z85Alphabet is the ZeroMQ Z85 alphabet
+	z85Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+	// uuLineSize is the number of bytes encoded on each uuencoded line
+	uuLineSize = 45
+)
+
+// errBase58TooLarge is returned for base58 text beyond encodingmodels.MaxBase58Size, which would take too long
+var errBase58TooLarge = errors.New("base58 text is limited to 4KB, as it is meant for short identifiers")
+
+// encodeBase58 encodes data using the given 58-character alphabet, preserving leading zero bytes
+func encodeBase58(data []byte, alphabet string) (string, error) {
+	// Each byte takes about 1.37 base58 digits
+	if len(data)*138/100 > encodingmodels.MaxBase58Size {
+		return "", errBase58TooLarge
+	}
+
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	// Repeatedly divide the big-endian number by 58, collecting little-endian digits
+	digits := make([]byte, 0, len(data)*138/100+1)
+	for _, b := range data[zeros:] {
+		carry := int(b)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(zeros + len(digits))
+	for i := 0; i < zeros; i++ {
+		sb.WriteByte(alphabet[0])
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		sb.WriteByte(alphabet[digits[i]])
+	}
+	return sb.String(), nil
+}
+
+// decodeBase58 decodes a base58 string using the given alphabet
+func decodeBase58(s, alphabet string) ([]byte, error) {
+	if len(s) > encodingmodels.MaxBase58Size {
+		return nil, errBase58TooLarge
+	}
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	// Multiply the accumulated little-endian bytes by 58 and add each digit
+	out := make([]byte, 0, len(s)*733/1000+1)
+	for i := zeros; i < len(s); i++ {
+		digit := strings.IndexByte(alphabet, s[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q at position %d", s[i], i)
+		}
+		carry := digit
+		for j := range out {
+			carry += int(out[j]) * 58
+			out[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			out = append(out, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	result := make([]byte, zeros+len(out))
+	for i := range out {
+		result[len(result)-1-i] = out[i]
+	}
+	return result, nil
+}
+
+// encodeBase85 encodes data in 4-byte groups using the given 85-character alphabet
+// A trailing partial group is padded with zeros and the extra characters are dropped
+func encodeBase85(data []byte, alphabet string) string {
+	var sb strings.Builder
+	sb.Grow((len(data) + 3) / 4 * 5)
+
+	for i := 0; i < len(data); i += 4 {
+		var chunk [4]byte
+		n := copy(chunk[:], data[i:])
+		value := uint32(chunk[0])<<24 | uint32(chunk[1])<<16 | uint32(chunk[2])<<8 | uint32(chunk[3])
+
+		var encoded [5]byte
+		for j := 4; j >= 0; j-- {
+			encoded[j] = alphabet[value%85]
+			value /= 85
+		}
+		sb.Write(encoded[:n+1])
+	}
+
+	return sb.String()
+}
+
+// decodeBase85 decodes data in 5-character groups using the given 85-character alphabet
+// A trailing partial group is padded with the highest digit and the extra bytes are dropped
+func decodeBase85(s, alphabet string) ([]byte, error) {
+	if len(s)%5 == 1 {
+		return nil, errors.New("invalid base85 length: a trailing group must have at least 2 characters")
+	}
+
+	out := make([]byte, 0, (len(s)+4)/5*4)
+	for i := 0; i < len(s); i += 5 {
+		end := min(i+5, len(s))
+		var value uint64
+		for j := i; j < i+5; j++ {
+			digit := 84
+			if j < end {
+				digit = strings.IndexByte(alphabet, s[j])
+				if digit < 0 {
+					return nil, fmt.Errorf("invalid base85 character %q at position %d", s[j], j)
+				}
+			}
+			value = value*85 + uint64(digit)
+		}
+		if value > 0xFFFFFFFF {
+			return nil, fmt.Errorf("invalid base85 group at position %d: value overflows 32 bits", i)
+		}
+		chunk := []byte{byte(value >> 24), byte(value >> 16), byte(value >> 8), byte(value)}
+		out = append(out, chunk[:end-i-1]...)
+	}
+
+	return out, nil
+}
+
+// encodeUU uuencodes data with a standard "begin 644 data" header and "end" trailer
+func encodeUU(data []byte) string {
+	var sb strings.Builder
+	sb.WriteString("begin 644 data\n")
+
+	for i := 0; i < len(data); i += uuLineSize {
+		line := data[i:min(i+uuLineSize, len(data))]
+		sb.WriteByte(uuChar(byte(len(line))))
+		for j := 0; j < len(line); j += 3 {
+			var chunk [3]byte
+			copy(chunk[:], line[j:])
+			sb.WriteByte(uuChar(chunk[0] >> 2))
+			sb.WriteByte(uuChar((chunk[0]<<4 | chunk[1]>>4) & 0x3F))
+			sb.WriteByte(uuChar((chunk[1]<<2 | chunk[2]>>6) & 0x3F))
+			sb.WriteByte(uuChar(chunk[2] & 0x3F))
+		}
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString("`\nend\n")
+	return sb.String()
+}
+
+// decodeUU decodes uuencoded text; the begin/end lines are optional
+func decodeUU(s string) ([]byte, error) {
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(s)+1)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "begin ") {
+			continue
+		}
+		if line == "end" {
+			break
+		}
+
+		n := int((line[0] - ' ') & 0x3F)
+		if n == 0 {
+			continue
+		}
+		body := line[1:]
+		if len(body) < (n+2)/3*4 {
+			return nil, fmt.Errorf("invalid uuencoded line %d: expected %d characters for %d bytes", lineNumber, (n+2)/3*4, n)
+		}
+
+		decoded := make([]byte, 0, (n+2)/3*3)
+		for j := 0; j+4 <= len(body) && len(decoded) < n; j += 4 {
+			var c [4]byte
+			for k := 0; k < 4; k++ {
+				if body[j+k] < ' ' || body[j+k] > '`' {
+					return nil, fmt.Errorf("invalid uuencoded character %q on line %d", body[j+k], lineNumber)
+				}
+				c[k] = (body[j+k] - ' ') & 0x3F
+			}
+			decoded = append(decoded, c[0]<<2|c[1]>>4, c[1]<<4|c[2]>>2, c[2]<<6|c[3])
+		}
+		out.Write(decoded[:n])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// uuChar maps a 6-bit value to its uuencoding character, using '`' instead of space for zero
+func uuChar(v byte) byte {
+	if v == 0 {
+		return '`'
+	}
+	return v + ' '
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/ascii85"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// codec converts between binary data and one textual encoding
+type codec struct {
+	description string
+	encode      func(data []byte) (string, error)
+	decode      func(s string) ([]byte, error)
+}
+
+var crockfordEncoding = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+// crockfordReplacer normalizes Crockford base32 input: hyphens are ignored and I/L/O are read as 1/1/0
+var crockfordReplacer = strings.NewReplacer("-", "", "I", "1", "L", "1", "O", "0")
+
+// codecNames lists the supported encodings in display order
+var codecNames = []string{
+	"text",
+	"bytes",
+	"hex",
+	"base64",
+	"base64-raw",
+	"base64url",
+	"base64url-raw",
+	"base32",
+	"base32-raw",
+	"base32hex",
+	"base32hex-raw",
+	"base32-crockford",
+	"base58",
+	"base58-flickr",
+	"base85",
+	"ascii85",
+	"z85",
+	"quoted-printable",
+	"uuencode",
+	"percent",
+	"percent-form",
+}
+
+var codecs = map[string]codec{
+	"text": {
+		description: "Raw UTF-8 text",
+		encode: func(data []byte) (string, error) {
+			if !utf8.Valid(data) {
+				return "", errors.New("data is not valid UTF-8 text, choose a binary-safe encoding")
+			}
+			return string(data), nil
+		},
+		decode: func(s string) ([]byte, error) { return []byte(s), nil },
+	},
+	"bytes": {
+		description: "Byte array such as [104 105]",
+		encode:      func(data []byte) (string, error) { return fmt.Sprintf("%v", data), nil },
+		decode:      parseByteArray,
+	},
+	"hex": {
+		description: "Base16, lowercase on output; 0x prefix, colons and whitespace are ignored on input",
+		encode:      func(data []byte) (string, error) { return hex.EncodeToString(data), nil },
+		decode: func(s string) ([]byte, error) {
+			s = strings.ReplaceAll(stripWhitespace(s), ":", "")
+			s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+			return hex.DecodeString(s)
+		},
+	},
+	"base64":        base64Codec("Base64 standard alphabet (RFC 4648), padded", base64.StdEncoding),
+	"base64-raw":    base64Codec("Base64 standard alphabet (RFC 4648), unpadded", base64.RawStdEncoding),
+	"base64url":     base64Codec("Base64 URL-safe alphabet (RFC 4648), padded", base64.URLEncoding),
+	"base64url-raw": base64Codec("Base64 URL-safe alphabet (RFC 4648), unpadded", base64.RawURLEncoding),
+	"base32":        base32Codec("Base32 standard alphabet (RFC 4648), padded", base32.StdEncoding),
+	"base32-raw":    base32Codec("Base32 standard alphabet (RFC 4648), unpadded", base32.StdEncoding.WithPadding(base32.NoPadding)),
+	"base32hex":     base32Codec("Base32 extended hex alphabet (RFC 4648), padded", base32.HexEncoding),
+	"base32hex-raw": base32Codec("Base32 extended hex alphabet (RFC 4648), unpadded", base32.HexEncoding.WithPadding(base32.NoPadding)),
+	"base32-crockford": {
+		description: "Crockford base32, case-insensitive, hyphens ignored on input",
+		encode:      func(data []byte) (string, error) { return crockfordEncoding.EncodeToString(data), nil },
+		decode: func(s string) ([]byte, error) {
+			s = crockfordReplacer.Replace(strings.ToUpper(stripWhitespace(s)))
+			return crockfordEncoding.DecodeString(s)
+		},
+	},
+	"base58": {
+		description: "Base58 Bitcoin alphabet",
+		encode:      func(data []byte) (string, error) { return encodeBase58(data, base58BitcoinAlphabet) },
+		decode:      func(s string) ([]byte, error) { return decodeBase58(stripWhitespace(s), base58BitcoinAlphabet) },
+	},
+	"base58-flickr": {
+		description: "Base58 Flickr alphabet",
+		encode:      func(data []byte) (string, error) { return encodeBase58(data, base58FlickrAlphabet) },
+		decode:      func(s string) ([]byte, error) { return decodeBase58(stripWhitespace(s), base58FlickrAlphabet) },
+	},
+	"base85": {
+		description: "Base85 RFC 1924 alphabet (as used by git and Python b85encode)",
+		encode:      func(data []byte) (string, error) { return encodeBase85(data, base85Alphabet), nil },
+		decode:      func(s string) ([]byte, error) { return decodeBase85(stripWhitespace(s), base85Alphabet) },
+	},
+	"ascii85": {
+		description: "Adobe Ascii85 without delimiters; <~ ~> delimiters are accepted on input",
+		encode: func(data []byte) (string, error) {
+			out := make([]byte, ascii85.MaxEncodedLen(len(data)))
+			n := ascii85.Encode(out, data)
+			return string(out[:n]), nil
+		},
+		decode: func(s string) ([]byte, error) {
+			s = strings.TrimSuffix(strings.TrimPrefix(stripWhitespace(s), "<~"), "~>")
+			// Each 'z' expands to 4 bytes, so this is the worst case size
+			out := make([]byte, 4*len(s)+4)
+			n, _, err := ascii85.Decode(out, []byte(s), true)
+			if err != nil {
+				return nil, err
+			}
+			return out[:n], nil
+		},
+	},
+	"z85": {
+		description: "ZeroMQ Z85; input length must be a multiple of 4 bytes",
+		encode: func(data []byte) (string, error) {
+			if len(data)%4 != 0 {
+				return "", errors.New("z85 requires the data length to be a multiple of 4 bytes, got " + strconv.Itoa(len(data)))
+			}
+			return encodeBase85(data, z85Alphabet), nil
+		},
+		decode: func(s string) ([]byte, error) {
+			s = stripWhitespace(s)
+			if len(s)%5 != 0 {
+				return nil, errors.New("z85 requires the string length to be a multiple of 5 characters, got " + strconv.Itoa(len(s)))
+			}
+			return decodeBase85(s, z85Alphabet)
+		},
+	},
+	"quoted-printable": {
+		description: "Quoted-printable (RFC 2045)",
+		encode: func(data []byte) (string, error) {
+			var buf bytes.Buffer
+			w := quotedprintable.NewWriter(&buf)
+			if _, err := w.Write(data); err != nil {
+				return "", err
+			}
+			if err := w.Close(); err != nil {
+				return "", err
+			}
+			return buf.String(), nil
+		},
+		decode: func(s string) ([]byte, error) {
+			return io.ReadAll(quotedprintable.NewReader(strings.NewReader(s)))
+		},
+	},
+	"uuencode": {
+		description: "Unix-to-Unix encoding with begin/end lines",
+		encode:      func(data []byte) (string, error) { return encodeUU(data), nil },
+		decode:      decodeUU,
+	},
+	"percent": {
+		description: "Percent-encoding (RFC 3986), only unreserved characters are left as-is",
+		encode:      func(data []byte) (string, error) { return percentEncode(data), nil },
+		decode: func(s string) ([]byte, error) {
+			decoded, err := url.PathUnescape(s)
+			return []byte(decoded), err
+		},
+	},
+	"percent-form": {
+		description: "application/x-www-form-urlencoded, spaces as '+'",
+		encode:      func(data []byte) (string, error) { return url.QueryEscape(string(data)), nil },
+		decode: func(s string) ([]byte, error) {
+			decoded, err := url.QueryUnescape(s)
+			return []byte(decoded), err
+		},
+	},
+}
+
+// base64Codec builds a codec for a base64 variant; decoding accepts either alphabet, with or without padding,
+// and tolerates whitespace, so every variant decodes the output of every other
+func base64Codec(description string, enc *base64.Encoding) codec {
+	return codec{
+		description: description,
+		encode:      func(data []byte) (string, error) { return enc.EncodeToString(data), nil },
+		decode:      decodeBase64,
+	}
+}
+
+// decodeBase64 decodes base64 in the URL-safe alphabet if it uses '-' or '_', otherwise in the standard one
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(stripWhitespace(s), "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
+
+// base32Codec builds a codec for a base32 variant; decoding tolerates whitespace, lowercase and missing padding
+func base32Codec(description string, enc *base32.Encoding) codec {
+	return codec{
+		description: description,
+		encode:      func(data []byte) (string, error) { return enc.EncodeToString(data), nil },
+		decode: func(s string) ([]byte, error) {
+			s = strings.ToUpper(strings.TrimRight(stripWhitespace(s), "="))
+			return enc.WithPadding(base32.NoPadding).DecodeString(s)
+		},
+	}
+}
+
+// percentEncode escapes every byte except RFC 3986 unreserved characters
+func percentEncode(data []byte) string {
+	const upperhex = "0123456789ABCDEF"
+	var sb strings.Builder
+	sb.Grow(len(data) * 3)
+	for _, b := range data {
+		if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') ||
+			b == '-' || b == '.' || b == '_' || b == '~' {
+			sb.WriteByte(b)
+			continue
+		}
+		sb.WriteByte('%')
+		sb.WriteByte(upperhex[b>>4])
+		sb.WriteByte(upperhex[b&0x0F])
+	}
+	return sb.String()
+}
+
+// stripWhitespace removes all whitespace, so wrapped or indented input can be decoded
+func stripWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
+
+// Parses a string like "[123 111 100]" or "[123, 111, 100]" into a byte slice
+func parseByteArray(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, errors.New("invalid byte array format: must be [123 111 100]")
+	}
+
+	s = strings.Trim(s, "[]")
+	s = strings.ReplaceAll(s, ",", " ")
+	parts := strings.Fields(s)
+	result := make([]byte, len(parts))
+
+	for i, part := range parts {
+		val, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid byte value '%s': %v", part, err)
+		}
+		if val < 0 || val > 255 {
+			return nil, fmt.Errorf("byte value %d out of range [0, 255]", val)
+		}
+		result[i] = byte(val)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+
+	encodingmodels "konverter/internal/encoding/models"
+)
+
+// Convert decodes the input from one encoding and re-encodes it in another
+func Convert(req encodingmodels.ConvertRequest) (encodingmodels.ConvertResponse, error) {
+	if err := req.Validate(); err != nil {
+		return encodingmodels.ConvertResponse{}, err
+	}
+
+	from, ok := codecs[strings.ToLower(req.From)]
+	if !ok {
+		return encodingmodels.ConvertResponse{}, errors.New("unsupported from encoding: " + req.From)
+	}
+	to, ok := codecs[strings.ToLower(req.To)]
+	if !ok {
+		return encodingmodels.ConvertResponse{}, errors.New("unsupported to encoding: " + req.To)
+	}
+
+	data, err := from.decode(req.Data)
+	if err != nil {
+		return encodingmodels.ConvertResponse{}, errors.New("failed to decode " + req.From + " data: " + err.Error())
+	}
+
+	output, err := to.encode(data)
+	if err != nil {
+		return encodingmodels.ConvertResponse{}, errors.New("failed to encode as " + req.To + ": " + err.Error())
+	}
+
+	return encodingmodels.ConvertResponse{
+		From:       strings.ToLower(req.From),
+		To:         strings.ToLower(req.To),
+		Output:     output,
+		ByteLength: len(data),
+	}, nil
+}
+
+// Encode encodes data in the named encoding, one of those listed by Formats
+// Other modules use it so that every endpoint reads and writes each encoding the same way
+func Encode(name string, data []byte) (string, error) {
+	c, ok := codecs[strings.ToLower(name)]
+	if !ok {
+		return "", errors.New("unsupported encoding: " + name)
+	}
+	return c.encode(data)
+}
+
+// Decode decodes s from the named encoding, one of those listed by Formats
+func Decode(name, s string) ([]byte, error) {
+	c, ok := codecs[strings.ToLower(name)]
+	if !ok {
+		return nil, errors.New("unsupported encoding: " + name)
+	}
+	return c.decode(s)
+}
+
+// Formats lists every supported encoding
+func Formats() []encodingmodels.Format {
+	formats := make([]encodingmodels.Format, 0, len(codecNames))
+	for _, name := range codecNames {
+		formats = append(formats, encodingmodels.Format{
+			Name:        name,
+			Description: codecs[name].description,
+		})
+	}
+	return formats
+}
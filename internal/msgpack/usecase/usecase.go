@@ -1,12 +1,10 @@
 package usecase
 
 import (
-	"encoding/base64"
 	"errors"
-	"fmt"
-	"strconv"
 	"strings"
 
+	encodingusecase "konverter/internal/encoding/usecase"
 	"konverter/internal/msgpack/models"
 
 	jsoniter "github.com/json-iterator/go"
@@ -34,11 +32,8 @@ func Encode(req models.EncodeRequest) (string, error) {
 
 	// Return as base64 encoded string or raw bytes
 	switch req.Type {
-	case "base64":
-		encoded := base64.StdEncoding.EncodeToString(msgpackData)
-		return encoded, nil
-	case "bytes":
-		return fmt.Sprintf("%v", msgpackData), nil
+	case "base64", "bytes":
+		return encodingusecase.Encode(req.Type, msgpackData)
 	default:
 		return "", errors.New("invalid request type: " + req.Type)
 	}
@@ -56,19 +51,19 @@ func Decode(req models.DecodeRequest) (any, error) {
 	// Decode from base64 encoded string or raw bytes
 	switch req.Type {
 	case "base64":
-		data, err = base64.StdEncoding.DecodeString(req.Data)
+		data, err = encodingusecase.Decode("base64", req.Data)
 		if err != nil {
 			return "", err
 		}
 	case "bytes":
 		// Try to parse as byte array format first, fallback to raw string
+		codec := "text"
 		if strings.HasPrefix(strings.TrimSpace(req.Data), "[") {
-			data, err = parseByteArray(req.Data)
-			if err != nil {
-				return "", err
-			}
-		} else {
-			data = []byte(req.Data)
+			codec = "bytes"
+		}
+		data, err = encodingusecase.Decode(codec, req.Data)
+		if err != nil {
+			return "", err
 		}
 	default:
 		return "", errors.New("invalid request type: " + req.Type)
@@ -83,35 +78,3 @@ func Decode(req models.DecodeRequest) (any, error) {
 
 	return decoded, nil
 }
-
-// Parses a string like "[123 111 100]" into a byte slice
-func parseByteArray(s string) ([]byte, error) {
-	// Remove brackets and trim spaces
-	s = strings.TrimSpace(s)
-	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
-		return nil, errors.New("invalid byte array format: must be [123 111 100]")
-	}
-
-	s = strings.Trim(s, "[]")
-	s = strings.ReplaceAll(s, ",", "")
-	if s == "" {
-		return []byte{}, nil
-	}
-
-	// Split by spaces
-	parts := strings.Fields(s)
-	result := make([]byte, len(parts))
-
-	for i, part := range parts {
-		val, err := strconv.Atoi(part)
-		if err != nil {
-			return nil, fmt.Errorf("invalid byte value '%s': %v", part, err)
-		}
-		if val < 0 || val > 255 {
-			return nil, fmt.Errorf("byte value %d out of range [0, 255]", val)
-		}
-		result[i] = byte(val)
-	}
-
-	return result, nil
-}
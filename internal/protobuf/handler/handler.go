@@ -0,0 +1,39 @@
+package protobuf
+
+import (
+	"konverter/internal/models"
+	protobufmodels "konverter/internal/protobuf/models"
+	"konverter/internal/protobuf/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Decode handles protobuf to JSON decoding requests
+func Decode(c *fiber.Ctx) error {
+	req := protobufmodels.DecodeRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	res, err := usecase.Decode(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
+
+// Encode handles JSON to protobuf encoding requests
+func Encode(c *fiber.Ctx) error {
+	req := protobufmodels.EncodeRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	res, err := usecase.Encode(req)
+	if err != nil {
+		return c.Status(fiber.StatusOK).JSON(models.Response{Success: false, Error: err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Success: true, Data: res})
+}
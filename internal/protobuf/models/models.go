@@ -0,0 +1,120 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	MaxSchemaSize = 1 * 1024 * 1024 // 1MB in bytes
+)
+
+type DecodeRequest struct {
+	// Type is the encoding of Data: "base64" or "hex"
+	Type string `json:"type"`
+	// Data is the protobuf wire-format payload
+	Data string `json:"data"`
+	// Schema is optional .proto source; without it the payload is decoded raw
+	Schema string `json:"schema,omitempty"`
+	// Message is the message name to decode as (e.g., "pkg.User" or "User")
+	Message string `json:"message,omitempty"`
+}
+
+func (r *DecodeRequest) Validate() error {
+	if r.Type != "base64" && r.Type != "hex" {
+		return errors.New("type must be either 'base64' or 'hex'")
+	}
+	if strings.TrimSpace(r.Data) == "" {
+		return errors.New("data is required")
+	}
+	if len(r.Schema) > MaxSchemaSize {
+		return errors.New("schema size exceeds maximum limit of 1MB")
+	}
+	if r.Schema == "" && r.Message != "" {
+		return errors.New("schema is required when message is set")
+	}
+	return nil
+}
+
+type DecodeResponse struct {
+	// Message is the fully-qualified message name used for decoding (schema mode only)
+	Message string `json:"message,omitempty"`
+	// Decoded is the proto3 JSON mapping of the message (schema mode only)
+	Decoded any `json:"decoded,omitempty"`
+	// Fields is the raw wire-format breakdown (schemaless mode only)
+	Fields []RawField `json:"fields,omitempty"`
+}
+
+// RawField describes a single field decoded from the wire format without a schema
+type RawField struct {
+	// Number is the field number
+	Number int32 `json:"number"`
+	// WireType is the numeric wire type (0, 1, 2, 5, 3/4 for groups)
+	WireType int8 `json:"wire_type"`
+	// WireTypeName is the name of the wire type (e.g., "varint", "len")
+	WireTypeName string `json:"wire_type_name"`
+	// Offset is the byte offset of the field tag in the enclosing message
+	Offset int `json:"offset"`
+	// Value holds the possible interpretations of the field payload
+	Value RawValue `json:"value"`
+}
+
+// RawValue holds every plausible interpretation of a field payload
+type RawValue struct {
+	// Uint is the value as an unsigned integer (varint/fixed32/fixed64)
+	Uint *uint64 `json:"uint,omitempty"`
+	// Int is the value as a two's complement signed integer
+	Int *int64 `json:"int,omitempty"`
+	// Sint is the zigzag-decoded value (varint only)
+	Sint *int64 `json:"sint,omitempty"`
+	// Float is the IEEE 754 interpretation (fixed32 as float, fixed64 as double)
+	Float *float64 `json:"float,omitempty"`
+	// Bool is set when a varint is 0 or 1
+	Bool *bool `json:"bool,omitempty"`
+	// String is set when a length-delimited payload is printable UTF-8
+	String *string `json:"string,omitempty"`
+	// Hex is the length-delimited payload as hex
+	Hex string `json:"hex,omitempty"`
+	// Length is the payload length for length-delimited fields
+	Length *int `json:"length,omitempty"`
+	// Message is set when a length-delimited payload parses as a nested message
+	Message []RawField `json:"message,omitempty"`
+	// Packed is set when a length-delimited payload parses as packed varints
+	Packed []uint64 `json:"packed,omitempty"`
+}
+
+type EncodeRequest struct {
+	// Type is the output encoding: "base64" or "hex"
+	Type string `json:"type"`
+	// Data is the JSON representation of the message (proto3 JSON mapping)
+	Data string `json:"data"`
+	// Schema is the .proto source
+	Schema string `json:"schema"`
+	// Message is the message name to encode as (e.g., "pkg.User" or "User")
+	Message string `json:"message,omitempty"`
+}
+
+func (r *EncodeRequest) Validate() error {
+	if r.Type != "base64" && r.Type != "hex" {
+		return errors.New("type must be either 'base64' or 'hex'")
+	}
+	if r.Data == "" {
+		return errors.New("data is required")
+	}
+	if r.Schema == "" {
+		return errors.New("schema is required")
+	}
+	if len(r.Schema) > MaxSchemaSize {
+		return errors.New("schema size exceeds maximum limit of 1MB")
+	}
+	return nil
+}
+
+type EncodeResponse struct {
+	// Message is the fully-qualified message name used for encoding
+	Message string `json:"message"`
+	// Encoded is the protobuf payload in the requested encoding
+	Encoded string `json:"encoded"`
+	// Type is the output encoding
+	Type string `json:"type"`
+}
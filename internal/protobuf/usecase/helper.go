@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	protobufmodels "konverter/internal/protobuf/models"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// schemaFileName is the virtual file name the request schema is compiled as
+	schemaFileName = "input.proto"
+	// maxRawDepth limits how deep nested-message guessing recurses
+	maxRawDepth = 16
+)
+
+// findMessage compiles the .proto source and resolves the requested message descriptor
+// If message is empty, the file must declare exactly one top-level message
+func findMessage(schema, message string) (protoreflect.MessageDescriptor, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{
+				schemaFileName: schema,
+			}),
+		}),
+	}
+
+	files, err := compiler.Compile(context.Background(), schemaFileName)
+	if err != nil {
+		return nil, errors.New("failed to parse schema: " + err.Error())
+	}
+	file := files[0]
+
+	if message == "" {
+		if file.Messages().Len() != 1 {
+			return nil, errors.New("message is required when the schema declares more than one message")
+		}
+		return file.Messages().Get(0), nil
+	}
+
+	// Try the name as given, then relative to the file package
+	candidates := []protoreflect.FullName{protoreflect.FullName(strings.TrimPrefix(message, "."))}
+	if pkg := file.Package(); pkg != "" {
+		candidates = append(candidates, pkg.Append(protoreflect.Name(message)))
+		if strings.Contains(message, ".") {
+			candidates = append(candidates, protoreflect.FullName(string(pkg)+"."+message))
+		}
+	}
+	for _, name := range candidates {
+		if !name.IsValid() {
+			continue
+		}
+		desc := file.FindDescriptorByName(name)
+		if md, ok := desc.(protoreflect.MessageDescriptor); ok {
+			return md, nil
+		}
+	}
+
+	return nil, errors.New("message not found in schema: " + message)
+}
+
+// decodeRaw walks the wire format without a schema and reports every field it finds
+func decodeRaw(data []byte, depth int) ([]protobufmodels.RawField, error) {
+	fields := []protobufmodels.RawField{}
+	offset := 0
+
+	for offset < len(data) {
+		num, typ, tagLen := protowire.ConsumeTag(data[offset:])
+		if tagLen < 0 {
+			return nil, protowire.ParseError(tagLen)
+		}
+		valueLen := protowire.ConsumeFieldValue(num, typ, data[offset+tagLen:])
+		if valueLen < 0 {
+			return nil, protowire.ParseError(valueLen)
+		}
+
+		field := protobufmodels.RawField{
+			Number:       int32(num),
+			WireType:     int8(typ),
+			WireTypeName: wireTypeName(typ),
+			Offset:       offset,
+		}
+		payload := data[offset+tagLen : offset+tagLen+valueLen]
+
+		switch typ {
+		case protowire.VarintType:
+			v, _ := protowire.ConsumeVarint(payload)
+			field.Value = varintValue(v)
+		case protowire.Fixed32Type:
+			v, _ := protowire.ConsumeFixed32(payload)
+			u := uint64(v)
+			i := int64(int32(v))
+			f := float64(math.Float32frombits(v))
+			field.Value = protobufmodels.RawValue{Uint: &u, Int: &i, Float: &f}
+		case protowire.Fixed64Type:
+			v, _ := protowire.ConsumeFixed64(payload)
+			i := int64(v)
+			f := math.Float64frombits(v)
+			field.Value = protobufmodels.RawValue{Uint: &v, Int: &i, Float: &f}
+		case protowire.BytesType:
+			b, _ := protowire.ConsumeBytes(payload)
+			field.Value = bytesValue(b, depth)
+		case protowire.StartGroupType:
+			// Group payload is everything up to the matching end-group tag
+			b, _ := protowire.ConsumeGroup(num, payload)
+			if depth < maxRawDepth {
+				if nested, err := decodeRaw(b, depth+1); err == nil {
+					field.Value.Message = nested
+				}
+			}
+			field.Value.Hex = hex.EncodeToString(b)
+		}
+
+		fields = append(fields, field)
+		offset += tagLen + valueLen
+	}
+
+	return fields, nil
+}
+
+// varintValue returns all common interpretations of a varint
+func varintValue(v uint64) protobufmodels.RawValue {
+	i := int64(v)
+	s := protowire.DecodeZigZag(v)
+	value := protobufmodels.RawValue{Uint: &v, Int: &i, Sint: &s}
+	if v <= 1 {
+		b := v == 1
+		value.Bool = &b
+	}
+	return value
+}
+
+// bytesValue guesses what a length-delimited payload contains: nested message, string, or packed varints
+func bytesValue(b []byte, depth int) protobufmodels.RawValue {
+	length := len(b)
+	value := protobufmodels.RawValue{Hex: hex.EncodeToString(b), Length: &length}
+	if length == 0 {
+		return value
+	}
+
+	if isPrintable(b) {
+		s := string(b)
+		value.String = &s
+	}
+
+	// A printable string that also parses as a message is most likely just a string
+	if depth < maxRawDepth && value.String == nil {
+		if nested, err := decodeRaw(b, depth+1); err == nil && len(nested) > 0 {
+			value.Message = nested
+		}
+	}
+
+	if value.Message == nil && value.String == nil {
+		if packed, ok := packedVarints(b); ok {
+			value.Packed = packed
+		}
+	}
+
+	return value
+}
+
+// packedVarints tries to read the payload as a packed repeated varint field
+func packedVarints(b []byte) ([]uint64, bool) {
+	values := []uint64{}
+	for len(b) > 0 {
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return nil, false
+		}
+		values = append(values, v)
+		b = b[n:]
+	}
+	return values, true
+}
+
+// isPrintable reports whether b is valid UTF-8 made of printable characters and common whitespace
+func isPrintable(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// wireTypeName returns the name of the wire type as used in the protobuf encoding docs
+func wireTypeName(typ protowire.Type) string {
+	switch typ {
+	case protowire.VarintType:
+		return "varint"
+	case protowire.Fixed64Type:
+		return "i64"
+	case protowire.BytesType:
+		return "len"
+	case protowire.StartGroupType:
+		return "sgroup"
+	case protowire.EndGroupType:
+		return "egroup"
+	case protowire.Fixed32Type:
+		return "i32"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"errors"
+
+	encodingusecase "konverter/internal/encoding/usecase"
+	protobufmodels "konverter/internal/protobuf/models"
+
+	jsoniter "github.com/json-iterator/go"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Decode decodes protobuf bytes to JSON using the given schema, or raw wire format without one
+func Decode(req protobufmodels.DecodeRequest) (protobufmodels.DecodeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return protobufmodels.DecodeResponse{}, err
+	}
+
+	data, err := encodingusecase.Decode(req.Type, req.Data)
+	if err != nil {
+		return protobufmodels.DecodeResponse{}, errors.New("failed to decode " + req.Type + " data: " + err.Error())
+	}
+
+	// Without a schema, fall back to raw wire-format decoding
+	if req.Schema == "" {
+		fields, err := decodeRaw(data, 0)
+		if err != nil {
+			return protobufmodels.DecodeResponse{}, errors.New("invalid protobuf wire format: " + err.Error())
+		}
+		return protobufmodels.DecodeResponse{Fields: fields}, nil
+	}
+
+	md, err := findMessage(req.Schema, req.Message)
+	if err != nil {
+		return protobufmodels.DecodeResponse{}, err
+	}
+
+	msg := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return protobufmodels.DecodeResponse{}, errors.New("failed to decode protobuf: " + err.Error())
+	}
+
+	// Convert using the proto3 JSON mapping, then re-parse so the response embeds an object
+	jsonBytes, err := protojson.Marshal(msg)
+	if err != nil {
+		return protobufmodels.DecodeResponse{}, errors.New("failed to convert protobuf to JSON: " + err.Error())
+	}
+	var decoded any
+	if err := jsoniter.Unmarshal(jsonBytes, &decoded); err != nil {
+		return protobufmodels.DecodeResponse{}, errors.New("failed to convert protobuf to JSON: " + err.Error())
+	}
+
+	return protobufmodels.DecodeResponse{
+		Message: string(md.FullName()),
+		Decoded: decoded,
+	}, nil
+}
+
+// Encode encodes JSON data (proto3 JSON mapping) to protobuf bytes using the given schema
+func Encode(req protobufmodels.EncodeRequest) (protobufmodels.EncodeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return protobufmodels.EncodeResponse{}, err
+	}
+
+	md, err := findMessage(req.Schema, req.Message)
+	if err != nil {
+		return protobufmodels.EncodeResponse{}, err
+	}
+
+	msg := dynamicpb.NewMessage(md)
+	if err := protojson.Unmarshal([]byte(req.Data), msg); err != nil {
+		return protobufmodels.EncodeResponse{}, errors.New("invalid JSON data: " + err.Error())
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return protobufmodels.EncodeResponse{}, errors.New("failed to encode protobuf: " + err.Error())
+	}
+
+	encoded, err := encodingusecase.Encode(req.Type, data)
+	if err != nil {
+		return protobufmodels.EncodeResponse{}, err
+	}
+
+	return protobufmodels.EncodeResponse{
+		Message: string(md.FullName()),
+		Encoded: encoded,
+		Type:    req.Type,
+	}, nil
+}
@@ -1,9 +1,15 @@
 package routes
 
 import (
+	avroHandler "konverter/internal/avro/handler"
+	compressHandler "konverter/internal/compress/handler"
+	cronHandler "konverter/internal/cron/handler"
 	cryptoHandler "konverter/internal/crypto/handler"
+	detectHandler "konverter/internal/detect/handler"
+	encodingHandler "konverter/internal/encoding/handler"
 	jsonHandler "konverter/internal/json/handler"
 	msgpackHandler "konverter/internal/msgpack/handler"
+	protobufHandler "konverter/internal/protobuf/handler"
 	timestampHandler "konverter/internal/timestamp/handler"
 	"time"
 
@@ -16,6 +22,12 @@ func SetupRouteV1(app *fiber.App) {
 	jsonRoutes(apiV1)
 	timestampRoutes(apiV1)
 	cryptoRoutes(apiV1)
+	protobufRoutes(apiV1)
+	avroRoutes(apiV1)
+	encodingRoutes(apiV1)
+	detectRoutes(apiV1)
+	compressRoutes(apiV1)
+	cronRoutes(apiV1)
 }
 
 func SetupFaviconRoute(app *fiber.App) {
@@ -55,10 +67,59 @@ func timestampRoutes(router fiber.Router) {
 	rTimestamp := router.Group("/timestamp")
 	rTimestamp.Post("/convert/humanize", timestampHandler.ConvertHumanize)
 	rTimestamp.Post("/convert/date-to-unix", timestampHandler.ConvertDateToUnix)
+	rTimestamp.Post("/convert/humanize/batch", timestampHandler.ConvertHumanizeBatch)
+	rTimestamp.Post("/convert/date-to-unix/batch", timestampHandler.ConvertDateToUnixBatch)
+	rTimestamp.Post("/convert/epoch", timestampHandler.ConvertEpoch)
+	rTimestamp.Post("/decode/id", timestampHandler.DecodeID)
+	rTimestamp.Post("/duration/parse", timestampHandler.ParseDuration)
+	rTimestamp.Post("/duration/add", timestampHandler.AddDuration)
+	rTimestamp.Post("/duration/between", timestampHandler.DurationBetween)
+	rTimestamp.Post("/timezones", timestampHandler.ListTimezones)
+	rTimestamp.Post("/timezones/transitions", timestampHandler.TimezoneTransitions)
+	rTimestamp.Post("/business-days/between", timestampHandler.BusinessDaysBetween)
+	rTimestamp.Post("/business-days/add", timestampHandler.AddBusinessDays)
+	rTimestamp.Post("/business-days/check", timestampHandler.CheckBusinessDay)
 }
 
 func cryptoRoutes(router fiber.Router) {
 	rCrypto := router.Group("/crypto")
 	rCrypto.Post("/encrypt", cryptoHandler.Encrypt)
 	rCrypto.Post("/decrypt", cryptoHandler.Decrypt)
+	rCrypto.Post("/inspect", cryptoHandler.Inspect)
+}
+
+func protobufRoutes(router fiber.Router) {
+	rProtobuf := router.Group("/protobuf")
+	rProtobuf.Post("/encode", protobufHandler.Encode)
+	rProtobuf.Post("/decode", protobufHandler.Decode)
+}
+
+func avroRoutes(router fiber.Router) {
+	rAvro := router.Group("/avro")
+	rAvro.Post("/encode", avroHandler.Encode)
+	rAvro.Post("/decode", avroHandler.Decode)
+	rAvro.Post("/container/decode", avroHandler.DecodeContainer)
+}
+
+func encodingRoutes(router fiber.Router) {
+	rEncoding := router.Group("/encoding")
+	rEncoding.Get("/formats", encodingHandler.Formats)
+	rEncoding.Post("/convert", encodingHandler.Convert)
+}
+
+func detectRoutes(router fiber.Router) {
+	router.Post("/detect", detectHandler.Detect)
+}
+
+func compressRoutes(router fiber.Router) {
+	rCompress := router.Group("/compress")
+	rCompress.Post("/compress", compressHandler.Compress)
+	rCompress.Post("/decompress", compressHandler.Decompress)
+}
+
+func cronRoutes(router fiber.Router) {
+	rCron := router.Group("/cron")
+	rCron.Post("/explain", cronHandler.Explain)
+	rCron.Post("/validate", cronHandler.Validate)
+	rCron.Post("/schedule", cronHandler.Schedule)
 }
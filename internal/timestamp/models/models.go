@@ -2,30 +2,57 @@ package models
 
 import (
 	"errors"
+	"strings"
 )
 
+// Units maps accepted unit names and abbreviations to the canonical unit name
+var Units = map[string]string{
+	"s":            "seconds",
+	"sec":          "seconds",
+	"seconds":      "seconds",
+	"ms":           "milliseconds",
+	"milliseconds": "milliseconds",
+	"us":           "microseconds",
+	"µs":           "microseconds",
+	"μs":           "microseconds",
+	"microseconds": "microseconds",
+	"ns":           "nanoseconds",
+	"nanoseconds":  "nanoseconds",
+}
+
 type ConvertHumanizeRequest struct {
-	// Timestamp is the unix timestamp input
-	Timestamp int64 `json:"timestamp"`
+	// Timestamp is the unix timestamp input; any int64 is accepted, including 0 and negative values
+	Timestamp *int64 `json:"timestamp"`
+	// Unit is optional explicit unit of Timestamp: "s", "ms", "us"/"µs" or "ns"; detected from magnitude when empty
+	Unit string `json:"unit,omitempty"`
 	// Timezone is optional timezone for timezone-specific output (e.g., "America/New_York", "Asia/Tokyo")
 	Timezone string `json:"timezone,omitempty"`
+	// OutputFormats is optional list of preset names (e.g., "rfc1123", "kitchen", "sql") or layouts in LayoutSyntax
+	OutputFormats []string `json:"output_formats,omitempty"`
+	// LayoutSyntax is the syntax of OutputFormats layouts: "go" (default), "strftime", "java"/"icu" or "moment"
+	LayoutSyntax string `json:"layout_syntax,omitempty"`
+	// Reference is optional reference time for relative output, as a date string or Unix seconds; defaults to now
+	Reference string `json:"reference,omitempty"`
+	// Locale is optional locale for relative output and month/weekday names in formatted output:
+	// "en" (default), "vi", "ja", "fr", "de" or "es", optionally with a region (e.g., "fr-CA"); others fall back to English
+	Locale string `json:"locale,omitempty"`
+	// Timezones is optional list of IANA zones or presets (configured as TIMEZONE_PRESET_<NAME>) for world clock output
+	Timezones []string `json:"timezones,omitempty"`
+	// BusinessHoursStart is the local start of business hours as HH:MM; defaults to 09:00
+	BusinessHoursStart string `json:"business_hours_start,omitempty"`
+	// BusinessHoursEnd is the local end of business hours as HH:MM; defaults to 17:00
+	BusinessHoursEnd string `json:"business_hours_end,omitempty"`
 }
 
 func (r *ConvertHumanizeRequest) Validate() error {
-	if r.Timestamp == 0 {
+	if r.Timestamp == nil {
 		return errors.New("timestamp is required")
 	}
 
-	// Check if timestamp is within reasonable bounds
-	// Earliest reasonable timestamp: 1970-01-01 (0)
-	// Latest reasonable timestamp: year 3000 (32503680000 seconds)
-	if r.Timestamp < 0 {
-		return errors.New("timestamp cannot be negative")
-	}
-
-	// If it's in microseconds, check it's not too far in the future
-	if r.Timestamp >= 10000000000000000 { // 10^16 microseconds = year ~316,000
-		return errors.New("timestamp is too far in the future")
+	if r.Unit != "" {
+		if _, ok := Units[r.Unit]; !ok {
+			return errors.New("unit must be one of 's', 'ms', 'us' or 'ns'")
+		}
 	}
 
 	return nil
@@ -34,53 +61,638 @@ func (r *ConvertHumanizeRequest) Validate() error {
 type ConvertHumanizeResponse struct {
 	// InputTimestamp is the original input timestamp
 	InputTimestamp int64 `json:"input_timestamp"`
-	// DetectedUnit indicates what unit was detected: "seconds", "milliseconds", or "microseconds"
+	// DetectedUnit indicates the unit used: "seconds", "milliseconds", "microseconds" or "nanoseconds"
 	DetectedUnit string `json:"detected_unit"`
 	// Seconds is the timestamp normalized to seconds
 	Seconds int64 `json:"seconds"`
-	// Milliseconds is the timestamp normalized to milliseconds
-	Milliseconds int64 `json:"milliseconds"`
-	// Microseconds is the timestamp normalized to microseconds
-	Microseconds int64 `json:"microseconds"`
-	// Nanoseconds is the timestamp normalized to nanoseconds
-	Nanoseconds int64 `json:"nanoseconds"`
-	// GMT is the time in RFC3339 format in GMT/UTC
+	// Milliseconds is the timestamp normalized to milliseconds, omitted when it overflows int64
+	Milliseconds *int64 `json:"milliseconds,omitempty"`
+	// Microseconds is the timestamp normalized to microseconds, omitted when it overflows int64
+	Microseconds *int64 `json:"microseconds,omitempty"`
+	// Nanoseconds is the timestamp normalized to nanoseconds, omitted when it overflows int64
+	Nanoseconds *int64 `json:"nanoseconds,omitempty"`
+	// GMT is the time in RFC3339 format in GMT/UTC, with fractional seconds when present
 	GMT string `json:"gmt"`
 	// TimezoneTime is the time in the specified timezone (if provided)
 	TimezoneTime string `json:"timezone_time,omitempty"`
-	// Relative is human-readable relative time (e.g., "2 hours ago", "in 5 minutes")
+	// Relative is human-readable relative time in Locale (e.g., "2 hours ago", "in 5 minutes")
 	Relative string `json:"relative"`
+	// RelativePrecise is the calendar difference from the reference time in Locale (e.g., "1 year, 3 months, 4 days ago"),
+	// with years, months and days counted in Timezone (UTC by default)
+	RelativePrecise string `json:"relative_precise,omitempty"`
+	// ReferenceTime is the reference time relative output was computed against
+	ReferenceTime string `json:"reference_time,omitempty"`
+	// Sentinels lists well-known special values this timestamp matches (e.g., "Unix epoch")
+	Sentinels []string `json:"sentinels,omitempty"`
+	// Formatted is the time rendered with each requested output format, in Timezone when given
+	Formatted map[string]string `json:"formatted,omitempty"`
+	// WorldClock is the time in each requested zone, in request order
+	WorldClock []ZoneTime `json:"world_clock,omitempty"`
+}
+
+// ZoneTime is an instant as seen in one timezone
+type ZoneTime struct {
+	// Timezone is the IANA zone name
+	Timezone string `json:"timezone"`
+	// LocalTime is the local time in RFC3339 format
+	LocalTime string `json:"local_time"`
+	// Weekday is the local day of the week
+	Weekday string `json:"weekday"`
+	// Offset is the UTC offset (e.g., "+07:00")
+	Offset string `json:"offset"`
+	// Abbreviation is the zone abbreviation (e.g., "CET")
+	Abbreviation string `json:"abbreviation"`
+	// DST is set when daylight saving time is in effect
+	DST bool `json:"dst"`
+	// BusinessHours is set when the local time is within business hours on a weekday
+	BusinessHours bool `json:"business_hours"`
 }
 
 type DateToUnixRequest struct {
-	// DateString is the input date string in various formats
+	// DateString is the input date string in various formats, an ISO 8601 week or ordinal date (e.g., "2025-W07-3", "2025-045"),
+	// or a relative expression (e.g., "now", "yesterday 14:00", "next friday", "3 days ago", "in 2h30m", "end of month")
 	DateString string `json:"date_string"`
-	// Timezone is optional timezone for timezone-specific parsing (e.g., "America/New_York", "Asia/Tokyo")
+	// Reference is optional reference time for relative expressions, as a date string or Unix seconds; defaults to now
+	Reference string `json:"reference,omitempty"`
+	// Timezone is optional timezone used to interpret date strings without a zone, and for output (e.g., "America/New_York", "Asia/Tokyo")
 	Timezone string `json:"timezone,omitempty"`
+	// Layout is optional parse layout in LayoutSyntax (e.g., "%d/%b/%Y:%H:%M:%S %z"); the built-in formats are tried when empty
+	Layout string `json:"layout,omitempty"`
+	// OutputFormats is optional list of preset names (e.g., "rfc1123", "kitchen", "sql") or layouts in LayoutSyntax
+	OutputFormats []string `json:"output_formats,omitempty"`
+	// LayoutSyntax is the syntax of Layout and OutputFormats: "go" (default), "strftime", "java"/"icu" or "moment"
+	LayoutSyntax string `json:"layout_syntax,omitempty"`
+	// DateOrder is optional preferred order for ambiguous numeric dates: "DMY", "MDY" or "YMD"
+	DateOrder string `json:"date_order,omitempty"`
+	// Locale is optional locale used to pick the date order when DateOrder is empty (e.g., "en-US", "en-GB", "ja"),
+	// and for month/weekday names in formatted output when it is "vi", "ja", "fr", "de" or "es"
+	Locale string `json:"locale,omitempty"`
+	// Strict makes ambiguous date strings an error unless DateOrder or Locale resolves them
+	Strict bool `json:"strict,omitempty"`
+	// Timezones is optional list of IANA zones or presets (configured as TIMEZONE_PRESET_<NAME>) for world clock output
+	Timezones []string `json:"timezones,omitempty"`
+	// BusinessHoursStart is the local start of business hours as HH:MM; defaults to 09:00
+	BusinessHoursStart string `json:"business_hours_start,omitempty"`
+	// BusinessHoursEnd is the local end of business hours as HH:MM; defaults to 17:00
+	BusinessHoursEnd string `json:"business_hours_end,omitempty"`
 }
 
 func (r *DateToUnixRequest) Validate() error {
 	if r.DateString == "" {
 		return errors.New("date_string is required")
 	}
-	return nil
+	return validateDateOrder(r.DateOrder)
+}
+
+// validateDateOrder checks an optional date_order
+func validateDateOrder(order string) error {
+	switch strings.ToUpper(order) {
+	case "", "DMY", "MDY", "YMD":
+		return nil
+	}
+	return errors.New("date_order must be one of 'DMY', 'MDY' or 'YMD'")
 }
 
 type DateToUnixResponse struct {
 	// InputDateString is the original input date string
 	InputDateString string `json:"input_date_string"`
-	// DetectedFormat indicates what format was detected and used for parsing
+	// DetectedFormat indicates what format was detected and used for parsing ("custom" for a caller-supplied layout)
 	DetectedFormat string `json:"detected_format"`
+	// Layout is the Go layout used for parsing, omitted for week, ordinal and relative dates
+	Layout string `json:"layout,omitempty"`
+	// ReferenceTime is the reference time a relative expression was resolved against
+	ReferenceTime string `json:"reference_time,omitempty"`
 	// Seconds is the timestamp in seconds
 	Seconds int64 `json:"seconds"`
-	// Milliseconds is the timestamp in milliseconds
-	Milliseconds int64 `json:"milliseconds"`
-	// Microseconds is the timestamp in microseconds
-	Microseconds int64 `json:"microseconds"`
-	// Nanoseconds is the timestamp in nanoseconds
-	Nanoseconds int64 `json:"nanoseconds"`
-	// GMT is the time in RFC3339 format in GMT/UTC
+	// Milliseconds is the timestamp in milliseconds, omitted when it overflows int64
+	Milliseconds *int64 `json:"milliseconds,omitempty"`
+	// Microseconds is the timestamp in microseconds, omitted when it overflows int64
+	Microseconds *int64 `json:"microseconds,omitempty"`
+	// Nanoseconds is the timestamp in nanoseconds, omitted when it overflows int64
+	Nanoseconds *int64 `json:"nanoseconds,omitempty"`
+	// GMT is the time in RFC3339 format in GMT/UTC, with fractional seconds when present
 	GMT string `json:"gmt"`
 	// TimezoneTime is the time in the specified timezone (if provided)
 	TimezoneTime string `json:"timezone_time,omitempty"`
+	// LocalTimeStatus is set when a zone-less date string was interpreted in Timezone:
+	// "unique", "gap" (the wall clock was skipped by a DST change) or "ambiguous" (it occurred twice)
+	LocalTimeStatus string `json:"local_time_status,omitempty"`
+	// Candidates lists both possible instants for "gap" and "ambiguous" local times; the first is the one returned
+	Candidates []LocalTimeCandidate `json:"candidates,omitempty"`
+	// Formatted is the time rendered with each requested output format, in Timezone when given
+	Formatted map[string]string `json:"formatted,omitempty"`
+	// Ambiguous is set when the date string matches formats that give different dates (e.g., D/M/Y and M/D/Y)
+	Ambiguous bool `json:"ambiguous,omitempty"`
+	// Interpretations lists every distinct reading of an ambiguous date string; the one returned is marked Chosen
+	Interpretations []DateInterpretation `json:"interpretations,omitempty"`
+	// WorldClock is the time in each requested zone, in request order
+	WorldClock []ZoneTime `json:"world_clock,omitempty"`
+}
+
+// DateInterpretation is one reading of an ambiguous date string
+type DateInterpretation struct {
+	// Format is the name of the format that produced this reading
+	Format string `json:"format"`
+	// Date is the date and time as read, without a zone
+	Date string `json:"date"`
+	// Seconds is the Unix timestamp in seconds
+	Seconds int64 `json:"seconds"`
+	// GMT is the instant in RFC3339 format in GMT/UTC
+	GMT string `json:"gmt"`
+	// Chosen marks the reading used for the rest of the response
+	Chosen bool `json:"chosen"`
+}
+
+// LocalTimeCandidate is one possible instant for a wall clock time in a timezone
+type LocalTimeCandidate struct {
+	// Seconds is the Unix timestamp in seconds
+	Seconds int64 `json:"seconds"`
+	// GMT is the instant in RFC3339 format in GMT/UTC
+	GMT string `json:"gmt"`
+	// TimezoneTime is the instant in the requested timezone
+	TimezoneTime string `json:"timezone_time"`
+	// Offset is the UTC offset applied (e.g., "-05:00")
+	Offset string `json:"offset"`
+	// Abbreviation is the zone abbreviation for that offset (e.g., "EST")
+	Abbreviation string `json:"abbreviation"`
+}
+
+type ConvertEpochRequest struct {
+	// Format is the encoding of Value (e.g., "filetime", "dotnet_ticks", "cocoa", "gps", "ntp", "excel", "julian_day", "tai64n")
+	Format string `json:"format"`
+	// Value is the encoded time as a string, so 64-bit, fractional and hex values are kept exact
+	Value string `json:"value"`
+	// Timezone is optional timezone for timezone-specific output (e.g., "America/New_York", "Asia/Tokyo")
+	Timezone string `json:"timezone,omitempty"`
+}
+
+func (r *ConvertEpochRequest) Validate() error {
+	if r.Format == "" {
+		return errors.New("format is required")
+	}
+	if strings.TrimSpace(r.Value) == "" {
+		return errors.New("value is required")
+	}
+	return nil
+}
+
+type ConvertEpochResponse struct {
+	// Format is the canonical name of the input format
+	Format string `json:"format"`
+	// InputValue is the original input value
+	InputValue string `json:"input_value"`
+	// Seconds is the Unix timestamp in seconds
+	Seconds int64 `json:"seconds"`
+	// Nanoseconds is the Unix timestamp in nanoseconds, omitted when it overflows int64
+	Nanoseconds *int64 `json:"nanoseconds,omitempty"`
+	// GMT is the time in RFC3339 format in GMT/UTC, with fractional seconds when present
+	GMT string `json:"gmt"`
+	// TimezoneTime is the time in the specified timezone (if provided)
+	TimezoneTime string `json:"timezone_time,omitempty"`
+	// Relative is human-readable relative time (e.g., "2 hours ago", "in 5 minutes")
+	Relative string `json:"relative"`
+	// Representations is the same instant in every supported format, keyed by format name
+	Representations map[string]string `json:"representations"`
+}
+
+type DecodeIDRequest struct {
+	// ID is the time-based identifier (ULID, UUID v1/v6/v7, Snowflake, KSUID or MongoDB ObjectId)
+	ID string `json:"id"`
+	// Type is optional: "ulid", "uuid", "snowflake", "ksuid" or "objectid"; detected from the shape when empty
+	Type string `json:"type,omitempty"`
+	// SnowflakePreset selects the Snowflake layout: "twitter" (default), "discord", "instagram", "sonyflake" or "custom"
+	SnowflakePreset string `json:"snowflake_preset,omitempty"`
+	// SnowflakeEpoch overrides the Snowflake epoch in Unix milliseconds (required for "custom")
+	SnowflakeEpoch *int64 `json:"snowflake_epoch,omitempty"`
+	// Timezone is optional timezone for timezone-specific output (e.g., "America/New_York", "Asia/Tokyo")
+	Timezone string `json:"timezone,omitempty"`
+}
+
+func (r *DecodeIDRequest) Validate() error {
+	if strings.TrimSpace(r.ID) == "" {
+		return errors.New("id is required")
+	}
+	switch r.Type {
+	case "", "ulid", "uuid", "snowflake", "ksuid", "objectid":
+	default:
+		return errors.New("type must be one of 'ulid', 'uuid', 'snowflake', 'ksuid' or 'objectid'")
+	}
+	return nil
+}
+
+type DecodeIDResponse struct {
+	// ID is the original input identifier
+	ID string `json:"id"`
+	// IDType is the identifier type that was decoded
+	IDType string `json:"id_type"`
+	// Fields holds the non-time components (e.g., node, sequence, worker_id, randomness)
+	Fields map[string]any `json:"fields,omitempty"`
+	// The embedded timestamp in the same shape as ConvertHumanize; InputTimestamp is the raw embedded value
+	ConvertHumanizeResponse
+}
+
+// DurationComponents is a duration split into calendar and clock parts; all parts share the duration's sign
+type DurationComponents struct {
+	Years   int   `json:"years"`
+	Months  int   `json:"months"`
+	Days    int   `json:"days"`
+	Hours   int64 `json:"hours"`
+	Minutes int64 `json:"minutes"`
+	Seconds int64 `json:"seconds"`
+	// Nanoseconds is the sub-second part
+	Nanoseconds int64 `json:"nanoseconds"`
+}
+
+// DurationTotals is a duration expressed entirely in each unit
+type DurationTotals struct {
+	// Nanoseconds is omitted when the total is approximate or overflows int64
+	Nanoseconds  *int64  `json:"nanoseconds,omitempty"`
+	Microseconds float64 `json:"microseconds"`
+	Milliseconds float64 `json:"milliseconds"`
+	Seconds      float64 `json:"seconds"`
+	Minutes      float64 `json:"minutes"`
+	Hours        float64 `json:"hours"`
+	Days         float64 `json:"days"`
+	Weeks        float64 `json:"weeks"`
+}
+
+type DurationRequest struct {
+	// Duration is a Go duration ("1h30m"), an ISO 8601 duration ("P1DT2H"), human text ("2 days 3 hours") or a bare number
+	Duration string `json:"duration"`
+	// Unit is the unit of a bare number Duration (e.g., "s", "ms", "h", "days"); defaults to seconds
+	Unit string `json:"unit,omitempty"`
+}
+
+func (r *DurationRequest) Validate() error {
+	if strings.TrimSpace(r.Duration) == "" {
+		return errors.New("duration is required")
+	}
+	return nil
+}
+
+type DurationResponse struct {
+	// Input is the original duration
+	Input string `json:"input"`
+	// Format is how the input was read: "go", "iso8601", "human" or "number"
+	Format string `json:"format"`
+	// Components are the parts as given; months and years are not folded into days
+	Components DurationComponents `json:"components"`
+	// ISO8601 is the duration in ISO 8601 format (e.g., "P1DT2H")
+	ISO8601 string `json:"iso8601"`
+	// GoDuration is the duration as a Go duration string, omitted when it has calendar parts
+	GoDuration string `json:"go_duration,omitempty"`
+	// Humanized is the duration as English text (e.g., "1 day, 2 hours")
+	Humanized string `json:"humanized"`
+	// Totals is the duration expressed in each unit
+	Totals DurationTotals `json:"totals"`
+	// Approximate is set when Totals use nominal lengths for days (24 hours) or months (30.436875 days)
+	Approximate bool `json:"approximate"`
+}
+
+type DurationAddRequest struct {
+	// Date is the start date in any format accepted by date-to-unix, including relative expressions
+	Date string `json:"date"`
+	// Duration is the duration to add, in any format accepted by the duration endpoint
+	Duration string `json:"duration"`
+	// Unit is the unit of a bare number Duration; defaults to seconds
+	Unit string `json:"unit,omitempty"`
+	// Subtract subtracts the duration instead of adding it
+	Subtract bool `json:"subtract,omitempty"`
+	// Timezone is optional timezone for calendar arithmetic and zone-less dates; defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
+	// DateOrder is the order of ambiguous numeric dates: "DMY", "MDY" or "YMD"; they are an error without it
+	DateOrder string `json:"date_order,omitempty"`
+}
+
+func (r *DurationAddRequest) Validate() error {
+	if strings.TrimSpace(r.Date) == "" {
+		return errors.New("date is required")
+	}
+	if strings.TrimSpace(r.Duration) == "" {
+		return errors.New("duration is required")
+	}
+	return validateDateOrder(r.DateOrder)
+}
+
+type DurationAddResponse struct {
+	// Start is the start time in RFC3339 format in Timezone (UTC by default)
+	Start string `json:"start"`
+	// Duration is the signed duration applied, in ISO 8601 format
+	Duration string `json:"duration"`
+	// Seconds is the result as a Unix timestamp in seconds
+	Seconds int64 `json:"seconds"`
+	// Milliseconds is the result as a Unix timestamp in milliseconds, omitted when it overflows int64
+	Milliseconds *int64 `json:"milliseconds,omitempty"`
+	// GMT is the result in RFC3339 format in GMT/UTC
+	GMT string `json:"gmt"`
+	// TimezoneTime is the result in the specified timezone (if provided)
+	TimezoneTime string `json:"timezone_time,omitempty"`
+	// Elapsed is the exact time between start and result, which differs from the nominal duration across DST changes;
+	// omitted beyond about 292 years
+	Elapsed string `json:"elapsed,omitempty"`
+}
+
+type DurationBetweenRequest struct {
+	// Start is the start date in any format accepted by date-to-unix, including relative expressions
+	Start string `json:"start"`
+	// End is the end date in any format accepted by date-to-unix, including relative expressions
+	End string `json:"end"`
+	// Timezone is optional timezone for calendar arithmetic and zone-less dates; defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
+	// DateOrder is the order of ambiguous numeric dates: "DMY", "MDY" or "YMD"; they are an error without it
+	DateOrder string `json:"date_order,omitempty"`
+}
+
+func (r *DurationBetweenRequest) Validate() error {
+	if strings.TrimSpace(r.Start) == "" {
+		return errors.New("start is required")
+	}
+	if strings.TrimSpace(r.End) == "" {
+		return errors.New("end is required")
+	}
+	return validateDateOrder(r.DateOrder)
+}
+
+type DurationBetweenResponse struct {
+	// Start is the start time in RFC3339 format in Timezone (UTC by default)
+	Start string `json:"start"`
+	// End is the end time in RFC3339 format in Timezone (UTC by default)
+	End string `json:"end"`
+	// Negative is set when End is before Start; the components then measure from End to Start
+	Negative bool `json:"negative"`
+	// Components is the calendar difference in whole years, months and days, then elapsed time
+	Components DurationComponents `json:"components"`
+	// ISO8601 is the calendar difference in ISO 8601 format, signed
+	ISO8601 string `json:"iso8601"`
+	// Humanized is the calendar difference as English text
+	Humanized string `json:"humanized"`
+	// Elapsed is the exact elapsed time as a Go duration string, omitted beyond about 292 years
+	Elapsed string `json:"elapsed,omitempty"`
+	// Totals is the exact elapsed time expressed in each unit
+	Totals DurationTotals `json:"totals"`
+}
+
+type BatchConvertHumanizeRequest struct {
+	// Timestamps are the unix timestamps to convert
+	Timestamps []int64 `json:"timestamps,omitempty"`
+	// Text is a newline-separated blob, e.g. pasted from logs; each line's first timestamp-sized integer is converted
+	Text string `json:"text,omitempty"`
+	// Options applied to every item (unit, timezone, output formats); timestamp is ignored
+	ConvertHumanizeRequest
+}
+
+type BatchConvertHumanizeItem struct {
+	// Index is the position of the item, counting Timestamps first and then the lines of Text
+	Index int `json:"index"`
+	// Input is the timestamp or text line as given
+	Input string `json:"input"`
+	// Result is the conversion, omitted on error
+	Result *ConvertHumanizeResponse `json:"result,omitempty"`
+	// Error is the reason the item could not be converted
+	Error string `json:"error,omitempty"`
+}
+
+type BatchConvertHumanizeResponse struct {
+	Results   []BatchConvertHumanizeItem `json:"results"`
+	Succeeded int                        `json:"succeeded"`
+	Failed    int                        `json:"failed"`
+}
+
+type BatchDateToUnixRequest struct {
+	// DateStrings are the date strings to convert
+	DateStrings []string `json:"date_strings,omitempty"`
+	// Text is a newline-separated blob, e.g. pasted from logs; each line's leading date is converted
+	Text string `json:"text,omitempty"`
+	// Options applied to every item (timezone, layout, date order, ...); date_string is ignored
+	DateToUnixRequest
+}
+
+type BatchDateToUnixItem struct {
+	// Index is the position of the item, counting DateStrings first and then the lines of Text
+	Index int `json:"index"`
+	// Input is the date string or text line as given
+	Input string `json:"input"`
+	// Result is the conversion, omitted on error
+	Result *DateToUnixResponse `json:"result,omitempty"`
+	// Error is the reason the item could not be converted
+	Error string `json:"error,omitempty"`
+}
+
+type BatchDateToUnixResponse struct {
+	Results   []BatchDateToUnixItem `json:"results"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+}
+
+// MaxTransitionRangeYears bounds the date range of a timezone transitions lookup
+const MaxTransitionRangeYears = 100
+
+type TimezonesRequest struct {
+	// Query optionally filters zones by a case-insensitive substring of the name (e.g., "europe", "york")
+	Query string `json:"query,omitempty"`
+	// At is optional instant for the offsets, as Unix seconds or any date-to-unix format; defaults to now
+	At string `json:"at,omitempty"`
+}
+
+// TimezoneInfo is a zone's offset at an instant
+type TimezoneInfo struct {
+	// Timezone is the IANA zone name
+	Timezone string `json:"timezone"`
+	// LocalTime is the local time in RFC3339 format
+	LocalTime string `json:"local_time"`
+	// Offset is the UTC offset (e.g., "+07:00")
+	Offset string `json:"offset"`
+	// OffsetSeconds is the UTC offset in seconds
+	OffsetSeconds int `json:"offset_seconds"`
+	// Abbreviation is the zone abbreviation (e.g., "CET")
+	Abbreviation string `json:"abbreviation"`
+	// DST is set when daylight saving time is in effect
+	DST bool `json:"dst"`
+	// NextTransition is the next offset change in RFC3339 format, omitted when none occurs within a year
+	NextTransition string `json:"next_transition,omitempty"`
+}
+
+type TimezonesResponse struct {
+	// At is the instant the offsets apply to, in RFC3339 format (UTC)
+	At        string         `json:"at"`
+	Count     int            `json:"count"`
+	Timezones []TimezoneInfo `json:"timezones"`
+}
+
+type TimezoneTransitionsRequest struct {
+	// Timezone is the IANA zone name (e.g., "Europe/Berlin")
+	Timezone string `json:"timezone"`
+	// Start is optional range start, as Unix seconds or any date-to-unix format; defaults to now
+	Start string `json:"start,omitempty"`
+	// End is optional range end, as Unix seconds or any date-to-unix format; defaults to one year after Start
+	End string `json:"end,omitempty"`
+}
+
+func (r *TimezoneTransitionsRequest) Validate() error {
+	if strings.TrimSpace(r.Timezone) == "" {
+		return errors.New("timezone is required")
+	}
+	return nil
+}
+
+// TimezoneTransition is a change of UTC offset in a zone
+type TimezoneTransition struct {
+	// Time is the first instant with the new offset, in RFC3339 format in the zone
+	Time string `json:"time"`
+	// GMT is the same instant in UTC
+	GMT string `json:"gmt"`
+	// Seconds is the instant as a Unix timestamp
+	Seconds int64 `json:"seconds"`
+	// OffsetBefore and OffsetAfter are the UTC offsets either side of the change (e.g., "+01:00")
+	OffsetBefore string `json:"offset_before"`
+	OffsetAfter  string `json:"offset_after"`
+	// AbbreviationBefore and AbbreviationAfter are the zone abbreviations either side of the change
+	AbbreviationBefore string `json:"abbreviation_before"`
+	AbbreviationAfter  string `json:"abbreviation_after"`
+	// DST is set when daylight saving time is in effect after the change
+	DST bool `json:"dst"`
+	// Description explains the wall clock change, e.g. "Clocks go forward 1h from 02:00 to 03:00"
+	Description string `json:"description"`
+}
+
+type TimezoneTransitionsResponse struct {
+	Timezone string `json:"timezone"`
+	// Start and End are the searched range in RFC3339 format in the zone
+	Start       string               `json:"start"`
+	End         string               `json:"end"`
+	Transitions []TimezoneTransition `json:"transitions"`
+}
+
+// MaxBusinessDays bounds the number of business days added in one request
+const MaxBusinessDays = 100000
+
+// BusinessCalendar selects the weekend and holidays that are not working days
+type BusinessCalendar struct {
+	// Timezone is optional timezone whose calendar dates are used, for date strings and instants; defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
+	// Calendars are optional bundled holiday calendars (e.g., "us-federal", "uk-england-wales", "target2")
+	Calendars []string `json:"calendars,omitempty"`
+	// Holidays are optional extra holidays as YYYY-MM-DD dates
+	Holidays []string `json:"holidays,omitempty"`
+	// ICS is optional iCalendar text whose all-day events are holidays; timed events are ignored
+	ICS string `json:"ics,omitempty"`
+	// Weekend is optional list of weekday names that are not working days; defaults to the first calendar's
+	// weekend, or Saturday and Sunday
+	Weekend []string `json:"weekend,omitempty"`
+	// DateOrder is the order of ambiguous numeric dates: "DMY", "MDY" or "YMD"; they are an error without it
+	DateOrder string `json:"date_order,omitempty"`
+}
+
+// Holiday is a non-working day from a holiday calendar
+type Holiday struct {
+	// Date is the holiday date as YYYY-MM-DD
+	Date string `json:"date"`
+	// Name is the holiday name, when known
+	Name string `json:"name,omitempty"`
+	// Calendar is the calendar the holiday came from: a bundled calendar name, "ics" or "request"
+	Calendar string `json:"calendar"`
+}
+
+type BusinessDaysBetweenRequest struct {
+	// Start is the start date in any format accepted by date-to-unix, including relative expressions
+	Start string `json:"start"`
+	// End is the end date in any format accepted by date-to-unix, including relative expressions
+	End string `json:"end"`
+	BusinessCalendar
+}
+
+func (r *BusinessDaysBetweenRequest) Validate() error {
+	if strings.TrimSpace(r.Start) == "" {
+		return errors.New("start is required")
+	}
+	if strings.TrimSpace(r.End) == "" {
+		return errors.New("end is required")
+	}
+	return validateDateOrder(r.DateOrder)
+}
+
+type BusinessDaysBetweenResponse struct {
+	// Start and End are the compared dates as YYYY-MM-DD
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// BusinessDays counts working days after Start up to and including End, negative when End is before Start,
+	// so adding BusinessDays to Start lands on End when End is a working day
+	BusinessDays int `json:"business_days"`
+	// CalendarDays is the number of calendar days from Start to End
+	CalendarDays int `json:"calendar_days"`
+	// WeekendDays and HolidayDays count the skipped days in the same range; holidays on a weekend count as weekend
+	WeekendDays int `json:"weekend_days"`
+	HolidayDays int `json:"holiday_days"`
+	// Holidays lists the skipped holidays that fall on working weekdays
+	Holidays []Holiday `json:"holidays"`
+	// Warnings notes dates outside the years a bundled calendar covers
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type BusinessDaysAddRequest struct {
+	// Date is the start date in any format accepted by date-to-unix, including relative expressions
+	Date string `json:"date"`
+	// Days is the number of business days to add; negative values count backwards
+	Days int `json:"days"`
+	BusinessCalendar
+}
+
+func (r *BusinessDaysAddRequest) Validate() error {
+	if strings.TrimSpace(r.Date) == "" {
+		return errors.New("date is required")
+	}
+	if r.Days > MaxBusinessDays || r.Days < -MaxBusinessDays {
+		return errors.New("days must be between -100000 and 100000")
+	}
+	return validateDateOrder(r.DateOrder)
+}
+
+type BusinessDaysAddResponse struct {
+	// Date is the start date as YYYY-MM-DD
+	Date string `json:"date"`
+	// Days is the number of business days added
+	Days int `json:"days"`
+	// Result is the resulting date as YYYY-MM-DD
+	Result string `json:"result"`
+	// ResultTime is the result at the start's time of day, in RFC3339 format in Timezone (UTC by default)
+	ResultTime string `json:"result_time"`
+	// Seconds is ResultTime as a Unix timestamp
+	Seconds int64 `json:"seconds"`
+	// CalendarDays is the number of calendar days from Date to Result
+	CalendarDays int `json:"calendar_days"`
+	// Holidays lists the holidays skipped on working weekdays
+	Holidays []Holiday `json:"holidays"`
+	// Warnings notes dates outside the years a bundled calendar covers
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type BusinessDayCheckRequest struct {
+	// Date is the date in any format accepted by date-to-unix, including relative expressions
+	Date string `json:"date"`
+	BusinessCalendar
+}
+
+func (r *BusinessDayCheckRequest) Validate() error {
+	if strings.TrimSpace(r.Date) == "" {
+		return errors.New("date is required")
+	}
+	return validateDateOrder(r.DateOrder)
+}
+
+type BusinessDayCheckResponse struct {
+	// Date is the checked date as YYYY-MM-DD
+	Date string `json:"date"`
+	// Weekday is the day of the week
+	Weekday string `json:"weekday"`
+	// WorkingDay is set when the date is neither a weekend day nor a holiday
+	WorkingDay bool `json:"working_day"`
+	// Weekend is set when the date falls on a weekend day
+	Weekend bool `json:"weekend"`
+	// Holidays lists the holidays on the date, from every calendar
+	Holidays []Holiday `json:"holidays,omitempty"`
+	// PreviousWorkingDay and NextWorkingDay are the nearest working days before and after the date, as YYYY-MM-DD
+	PreviousWorkingDay string `json:"previous_working_day"`
+	NextWorkingDay     string `json:"next_working_day"`
+	// Warnings notes dates outside the years a bundled calendar covers
+	Warnings []string `json:"warnings,omitempty"`
 }
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	timestampmodels "konverter/internal/timestamp/models"
+)
+
+// maxBatchItems caps the number of values converted in one batch request
+const maxBatchItems = 1000
+
+// maxDatePrefixFields is how many leading whitespace-separated fields of a log line are tried as a date
+const maxDatePrefixFields = 6
+
+// embeddedTimestampPattern finds a Unix timestamp of at least seconds precision inside a log line
+var embeddedTimestampPattern = regexp.MustCompile(`-?\b\d{9,19}\b`)
+
+// splitBatchLines returns the non-empty trimmed lines of a pasted text blob
+func splitBatchLines(text string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// extractTimestamp reads a line that is a timestamp, or finds the first timestamp-sized integer in it
+func extractTimestamp(line string) (int64, error) {
+	if timestamp, err := strconv.ParseInt(line, 10, 64); err == nil {
+		return timestamp, nil
+	}
+	match := embeddedTimestampPattern.FindString(line)
+	if match == "" {
+		return 0, errors.New("no timestamp found in line")
+	}
+	return strconv.ParseInt(match, 10, 64)
+}
+
+// convertDateLine converts the longest leading run of fields in a log line that parses as a date,
+// ignoring surrounding brackets, so "[2024-03-10 02:30:00] ERROR ..." converts its timestamp
+func convertDateLine(line string, options timestampmodels.DateToUnixRequest) (timestampmodels.DateToUnixResponse, error) {
+	options.DateString = line
+	response, err := ConvertDateToUnix(options)
+	if err == nil {
+		return response, nil
+	}
+
+	fields := strings.Fields(line)
+	for n := min(len(fields), maxDatePrefixFields); n > 0; n-- {
+		options.DateString = strings.Trim(strings.Join(fields[:n], " "), "[]()")
+		if options.DateString == "" {
+			continue
+		}
+		if prefixResponse, prefixErr := ConvertDateToUnix(options); prefixErr == nil {
+			return prefixResponse, nil
+		}
+	}
+	return timestampmodels.DateToUnixResponse{}, err
+}
+
+// ConvertHumanizeBatch converts many timestamps, reporting a result or an error for each
+func ConvertHumanizeBatch(req timestampmodels.BatchConvertHumanizeRequest) (timestampmodels.BatchConvertHumanizeResponse, error) {
+	lines := splitBatchLines(req.Text)
+	if len(req.Timestamps)+len(lines) == 0 {
+		return timestampmodels.BatchConvertHumanizeResponse{}, errors.New("timestamps or text is required")
+	}
+	if len(req.Timestamps)+len(lines) > maxBatchItems {
+		return timestampmodels.BatchConvertHumanizeResponse{}, fmt.Errorf("at most %d items are allowed per batch", maxBatchItems)
+	}
+
+	response := timestampmodels.BatchConvertHumanizeResponse{Results: []timestampmodels.BatchConvertHumanizeItem{}}
+	convert := func(input string, timestamp int64, err error) {
+		item := timestampmodels.BatchConvertHumanizeItem{Index: len(response.Results), Input: input}
+		if err == nil {
+			options := req.ConvertHumanizeRequest
+			options.Timestamp = &timestamp
+			var result timestampmodels.ConvertHumanizeResponse
+			if result, err = ConvertHumanize(options); err == nil {
+				item.Result = &result
+			}
+		}
+		if err != nil {
+			item.Error = err.Error()
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, item)
+	}
+
+	for _, timestamp := range req.Timestamps {
+		convert(strconv.FormatInt(timestamp, 10), timestamp, nil)
+	}
+	for _, line := range lines {
+		timestamp, err := extractTimestamp(line)
+		convert(line, timestamp, err)
+	}
+
+	return response, nil
+}
+
+// ConvertDateToUnixBatch converts many date strings, reporting a result or an error for each
+func ConvertDateToUnixBatch(req timestampmodels.BatchDateToUnixRequest) (timestampmodels.BatchDateToUnixResponse, error) {
+	inputs := append(append([]string{}, req.DateStrings...), splitBatchLines(req.Text)...)
+	if len(inputs) == 0 {
+		return timestampmodels.BatchDateToUnixResponse{}, errors.New("date_strings or text is required")
+	}
+	if len(inputs) > maxBatchItems {
+		return timestampmodels.BatchDateToUnixResponse{}, fmt.Errorf("at most %d items are allowed per batch", maxBatchItems)
+	}
+
+	response := timestampmodels.BatchDateToUnixResponse{Results: []timestampmodels.BatchDateToUnixItem{}}
+	for i, input := range inputs {
+		item := timestampmodels.BatchDateToUnixItem{Index: i, Input: input}
+
+		// Explicit date strings must parse whole; pasted lines may carry trailing log text
+		var result timestampmodels.DateToUnixResponse
+		var err error
+		if i < len(req.DateStrings) {
+			options := req.DateToUnixRequest
+			options.DateString = input
+			result, err = ConvertDateToUnix(options)
+		} else {
+			result, err = convertDateLine(input, req.DateToUnixRequest)
+		}
+
+		if err != nil {
+			item.Error = err.Error()
+			response.Failed++
+		} else {
+			item.Result = &result
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, item)
+	}
+
+	return response, nil
+}
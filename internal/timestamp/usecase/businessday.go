@@ -0,0 +1,555 @@
+package usecase
+
+import (
+	"embed"
+	"errors"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	timestampmodels "konverter/internal/timestamp/models"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// holidayFiles are the bundled holiday calendars, as JSON or iCalendar files named after the calendar
+//
+//go:embed holidays
+var holidayFiles embed.FS
+
+// bundledCalendar is a holiday calendar shipped with the binary
+type bundledCalendar struct {
+	weekend   []time.Weekday
+	holidays  []calendarHoliday
+	firstYear int
+	lastYear  int
+}
+
+// calendarHoliday is a holiday on a date, or on a month and day every year from date when yearly is set
+type calendarHoliday struct {
+	date   time.Time
+	name   string
+	yearly bool
+	// until is the last year of a yearly holiday, zero when it does not end
+	until int
+	// calendar is where the holiday came from: a bundled calendar name, "ics" or "request"
+	calendar string
+}
+
+// holidayCalendarFile is the JSON holiday calendar format
+type holidayCalendarFile struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Weekend     []string `json:"weekend"`
+	Holidays    []struct {
+		Date string `json:"date"`
+		Name string `json:"name"`
+	} `json:"holidays"`
+}
+
+// bundledCalendars are the bundled holiday calendars keyed by name, loaded once at startup
+var bundledCalendars = loadBundledCalendars()
+
+// loadBundledCalendars parses the embedded holiday files; they are part of the source, so errors panic
+func loadBundledCalendars() map[string]bundledCalendar {
+	entries, err := holidayFiles.ReadDir("holidays")
+	if err != nil {
+		panic(err)
+	}
+
+	calendars := map[string]bundledCalendar{}
+	for _, entry := range entries {
+		data, err := holidayFiles.ReadFile("holidays/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+		ext := path.Ext(entry.Name())
+		name := strings.TrimSuffix(entry.Name(), ext)
+
+		calendar := bundledCalendar{weekend: []time.Weekday{time.Saturday, time.Sunday}}
+		switch ext {
+		case ".json":
+			var file holidayCalendarFile
+			if err := jsoniter.Unmarshal(data, &file); err != nil {
+				panic("holiday calendar " + entry.Name() + ": " + err.Error())
+			}
+			if calendar.weekend, err = parseWeekend(file.Weekend); err != nil {
+				panic("holiday calendar " + entry.Name() + ": " + err.Error())
+			}
+			for _, holiday := range file.Holidays {
+				date, err := time.Parse(time.DateOnly, holiday.Date)
+				if err != nil {
+					panic("holiday calendar " + entry.Name() + ": " + err.Error())
+				}
+				calendar.holidays = append(calendar.holidays, calendarHoliday{date: date, name: holiday.Name})
+			}
+		case ".ics":
+			if calendar.holidays, err = parseICS(string(data)); err != nil {
+				panic("holiday calendar " + entry.Name() + ": " + err.Error())
+			}
+		default:
+			continue
+		}
+
+		for _, holiday := range calendar.holidays {
+			if year := holiday.date.Year(); calendar.firstYear == 0 || year < calendar.firstYear {
+				calendar.firstYear = year
+			}
+			calendar.lastYear = max(calendar.lastYear, holiday.date.Year())
+		}
+		calendars[name] = calendar
+	}
+	return calendars
+}
+
+// bundledCalendarNames lists the bundled calendar names in order
+func bundledCalendarNames() []string {
+	names := make([]string, 0, len(bundledCalendars))
+	for name := range bundledCalendars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// parseWeekend parses weekday names, defaulting to Saturday and Sunday when empty
+func parseWeekend(names []string) ([]time.Weekday, error) {
+	if len(names) == 0 {
+		return []time.Weekday{time.Saturday, time.Sunday}, nil
+	}
+	weekend := []time.Weekday{}
+	for _, name := range names {
+		weekday, ok := weekdays[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, errors.New("unknown weekday: " + name)
+		}
+		if !slices.Contains(weekend, weekday) {
+			weekend = append(weekend, weekday)
+		}
+	}
+	if len(weekend) == 7 {
+		return nil, errors.New("weekend must leave at least one working day")
+	}
+	return weekend, nil
+}
+
+// parseICS reads the all-day events of an iCalendar file as holidays, skipping timed events
+// Multi-day events cover every day up to DTEND; RRULE is supported for plain yearly repetition
+func parseICS(text string) ([]calendarHoliday, error) {
+	// Unfold continuation lines
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\n ", ""), "\n\t", "")
+
+	holidays := []calendarHoliday{}
+	var event map[string]string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		nameParams, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.ToUpper(nameParams), ";")
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			event = map[string]string{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if event == nil {
+				return nil, errors.New("invalid iCalendar: END:VEVENT without BEGIN:VEVENT")
+			}
+			eventHolidays, err := icsEventHolidays(event)
+			if err != nil {
+				return nil, err
+			}
+			holidays = append(holidays, eventHolidays...)
+			event = nil
+		case event != nil:
+			event[name] = value
+		}
+	}
+	if event != nil {
+		return nil, errors.New("invalid iCalendar: VEVENT is not closed")
+	}
+	return holidays, nil
+}
+
+// icsEventHolidays converts one VEVENT's properties to holidays; timed events, whose DTSTART is a DATE-TIME, have none
+func icsEventHolidays(event map[string]string) ([]calendarHoliday, error) {
+	summary := strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(event["SUMMARY"])
+	if strings.Contains(event["DTSTART"], "T") {
+		return nil, nil
+	}
+
+	start, err := parseICSDate(event["DTSTART"])
+	if err != nil {
+		return nil, errors.New("invalid iCalendar DTSTART in " + strconv.Quote(summary) + ": " + err.Error())
+	}
+	end := start.AddDate(0, 0, 1)
+	if event["DTEND"] != "" {
+		if end, err = parseICSDate(event["DTEND"]); err != nil {
+			return nil, errors.New("invalid iCalendar DTEND in " + strconv.Quote(summary) + ": " + err.Error())
+		}
+		// DTEND is exclusive
+		if !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+	}
+	if end.After(start.AddDate(1, 0, 0)) {
+		return nil, errors.New("iCalendar event " + strconv.Quote(summary) + " is longer than a year")
+	}
+
+	yearly, until, err := parseICSRule(event["RRULE"], start)
+	if err != nil {
+		return nil, errors.New("iCalendar event " + strconv.Quote(summary) + ": " + err.Error())
+	}
+
+	holidays := []calendarHoliday{}
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		holidays = append(holidays, calendarHoliday{date: day, name: summary, yearly: yearly, until: until})
+	}
+	return holidays, nil
+}
+
+// parseICSDate parses an iCalendar DATE or DATE-TIME value, keeping its calendar date
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, errors.New("expected YYYYMMDD, got " + strconv.Quote(value))
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+// parseICSRule parses an RRULE, accepting only yearly repetition without BY parts
+// It returns whether the event repeats yearly and its last year, zero when unbounded
+func parseICSRule(rule string, start time.Time) (bool, int, error) {
+	if rule == "" {
+		return false, 0, nil
+	}
+
+	yearly, until := false, 0
+	for _, part := range strings.Split(strings.ToUpper(rule), ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "FREQ":
+			if value != "YEARLY" {
+				return false, 0, errors.New("only FREQ=YEARLY recurrence is supported")
+			}
+			yearly = true
+		case "INTERVAL":
+			if value != "1" {
+				return false, 0, errors.New("only INTERVAL=1 recurrence is supported")
+			}
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return false, 0, errors.New("invalid RRULE COUNT: " + value)
+			}
+			until = start.Year() + count - 1
+		case "UNTIL":
+			date, err := parseICSDate(value)
+			if err != nil {
+				return false, 0, errors.New("invalid RRULE UNTIL: " + value)
+			}
+			until = date.Year()
+		case "WKST":
+		default:
+			return false, 0, errors.New("unsupported RRULE part: " + key)
+		}
+	}
+	if !yearly {
+		return false, 0, errors.New("RRULE has no FREQ")
+	}
+	return true, until, nil
+}
+
+// businessCalendar decides which dates are working days
+type businessCalendar struct {
+	loc     *time.Location
+	weekend []time.Weekday
+	// order is the date order for ambiguous dates
+	order    string
+	holidays map[string][]calendarHoliday
+	// bundled are the bundled calendars in use, for coverage warnings
+	bundled []string
+}
+
+// newBusinessCalendar builds the calendar a request describes
+func newBusinessCalendar(req timestampmodels.BusinessCalendar) (*businessCalendar, error) {
+	calendar := &businessCalendar{loc: time.UTC, holidays: map[string][]calendarHoliday{}, order: req.DateOrder}
+	if req.Timezone != "" {
+		var err error
+		if calendar.loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, errors.New("invalid timezone: " + err.Error())
+		}
+	}
+
+	for _, name := range req.Calendars {
+		name = strings.ToLower(strings.TrimSpace(name))
+		bundled, ok := bundledCalendars[name]
+		if !ok {
+			return nil, errors.New("unknown holiday calendar: " + name + ", expected one of " + strings.Join(bundledCalendarNames(), ", "))
+		}
+		if calendar.weekend == nil {
+			calendar.weekend = bundled.weekend
+		}
+		calendar.add(name, bundled.holidays)
+		calendar.bundled = append(calendar.bundled, name)
+	}
+
+	if len(req.Weekend) > 0 || calendar.weekend == nil {
+		var err error
+		if calendar.weekend, err = parseWeekend(req.Weekend); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ICS != "" {
+		holidays, err := parseICS(req.ICS)
+		if err != nil {
+			return nil, err
+		}
+		calendar.add("ics", holidays)
+	}
+
+	for _, holiday := range req.Holidays {
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(holiday))
+		if err != nil {
+			return nil, errors.New("invalid holiday date, expected YYYY-MM-DD: " + holiday)
+		}
+		calendar.add("request", []calendarHoliday{{date: date}})
+	}
+
+	if calendar.leavesNoWorkingDays() {
+		return nil, errors.New("holiday calendar leaves no working days")
+	}
+
+	return calendar, nil
+}
+
+// add indexes holidays from source under their date, or under their month and day when yearly
+func (c *businessCalendar) add(source string, holidays []calendarHoliday) {
+	for _, holiday := range holidays {
+		holiday.calendar = source
+		key := holiday.date.Format(time.DateOnly)
+		if holiday.yearly {
+			key = holiday.date.Format("01-02")
+		}
+		c.holidays[key] = append(c.holidays[key], holiday)
+	}
+}
+
+// date resolves a date string to its calendar date in the calendar's timezone, also returning the instant
+func (c *businessCalendar) date(dateString string) (time.Time, time.Time, error) {
+	t, err := resolveDate(dateString, c.order, c.loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return wallDate(t, c.loc), t, nil
+}
+
+// leavesNoWorkingDays reports whether yearly holidays without an end cover every day of the year
+// Weekends alone never do, since they leave a working day and the weekday of a date shifts from year to year
+func (c *businessCalendar) leavesNoWorkingDays() bool {
+	for day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); day.Year() == 2024; day = day.AddDate(0, 0, 1) {
+		unbounded := func(holiday calendarHoliday) bool { return holiday.until == 0 }
+		if !slices.ContainsFunc(c.holidays[day.Format("01-02")], unbounded) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWeekend reports whether date falls on a weekend day
+func (c *businessCalendar) isWeekend(date time.Time) bool {
+	return slices.Contains(c.weekend, date.Weekday())
+}
+
+// holidaysOn lists the holidays on date from every calendar
+func (c *businessCalendar) holidaysOn(date time.Time) []calendarHoliday {
+	holidays := slices.Clone(c.holidays[date.Format(time.DateOnly)])
+	for _, holiday := range c.holidays[date.Format("01-02")] {
+		if date.Year() >= holiday.date.Year() && (holiday.until == 0 || date.Year() <= holiday.until) {
+			holidays = append(holidays, holiday)
+		}
+	}
+	return holidays
+}
+
+// isWorkingDay reports whether date is neither a weekend day nor a holiday
+func (c *businessCalendar) isWorkingDay(date time.Time) bool {
+	return !c.isWeekend(date) && len(c.holidaysOn(date)) == 0
+}
+
+// warnings notes bundled calendars that do not cover every year from first to last
+func (c *businessCalendar) warnings(first, last time.Time) []string {
+	if last.Before(first) {
+		first, last = last, first
+	}
+	warnings := []string{}
+	for _, name := range c.bundled {
+		bundled := bundledCalendars[name]
+		if first.Year() < bundled.firstYear || last.Year() > bundled.lastYear {
+			warnings = append(warnings, "holiday calendar "+name+" only covers "+strconv.Itoa(bundled.firstYear)+" through "+strconv.Itoa(bundled.lastYear))
+		}
+	}
+	return warnings
+}
+
+// holidayModels converts the holidays on date for a response
+func holidayModels(date time.Time, holidays []calendarHoliday) []timestampmodels.Holiday {
+	models := make([]timestampmodels.Holiday, 0, len(holidays))
+	for _, holiday := range holidays {
+		models = append(models, timestampmodels.Holiday{Date: date.Format(time.DateOnly), Name: holiday.name, Calendar: holiday.calendar})
+	}
+	return models
+}
+
+// BusinessDaysBetween counts the working days between two dates
+func BusinessDaysBetween(req timestampmodels.BusinessDaysBetweenRequest) (timestampmodels.BusinessDaysBetweenResponse, error) {
+	if err := req.Validate(); err != nil {
+		return timestampmodels.BusinessDaysBetweenResponse{}, err
+	}
+
+	calendar, err := newBusinessCalendar(req.BusinessCalendar)
+	if err != nil {
+		return timestampmodels.BusinessDaysBetweenResponse{}, err
+	}
+	start, _, err := calendar.date(req.Start)
+	if err != nil {
+		return timestampmodels.BusinessDaysBetweenResponse{}, errors.New("invalid start: " + err.Error())
+	}
+	end, _, err := calendar.date(req.End)
+	if err != nil {
+		return timestampmodels.BusinessDaysBetweenResponse{}, errors.New("invalid end: " + err.Error())
+	}
+
+	calendarDays := int(end.Sub(start) / nominalDay)
+	if calendarDays > timestampmodels.MaxBusinessDays || calendarDays < -timestampmodels.MaxBusinessDays {
+		return timestampmodels.BusinessDaysBetweenResponse{}, errors.New("start and end must be at most 100000 days apart")
+	}
+
+	response := timestampmodels.BusinessDaysBetweenResponse{
+		Start:        start.Format(time.DateOnly),
+		End:          end.Format(time.DateOnly),
+		CalendarDays: calendarDays,
+		Holidays:     []timestampmodels.Holiday{},
+		Warnings:     calendar.warnings(start, end),
+	}
+
+	// Count the days after start up to end going forward, or from end up to before start going backward
+	first, last, sign := start.AddDate(0, 0, 1), end, 1
+	if end.Before(start) {
+		first, last, sign = end, start.AddDate(0, 0, -1), -1
+	}
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		holidays := calendar.holidaysOn(day)
+		switch {
+		case calendar.isWeekend(day):
+			response.WeekendDays++
+		case len(holidays) > 0:
+			response.HolidayDays++
+			response.Holidays = append(response.Holidays, holidayModels(day, holidays)...)
+		default:
+			response.BusinessDays += sign
+		}
+	}
+
+	return response, nil
+}
+
+// maxBusinessScanDays bounds the calendar days AddBusinessDays looks through, which is enough for
+// MaxBusinessDays working days with a six-day weekend and leaves room for holidays
+const maxBusinessScanDays = 8 * timestampmodels.MaxBusinessDays
+
+// AddBusinessDays moves a date forward or backward by a number of working days, keeping its time of day
+func AddBusinessDays(req timestampmodels.BusinessDaysAddRequest) (timestampmodels.BusinessDaysAddResponse, error) {
+	if err := req.Validate(); err != nil {
+		return timestampmodels.BusinessDaysAddResponse{}, err
+	}
+
+	calendar, err := newBusinessCalendar(req.BusinessCalendar)
+	if err != nil {
+		return timestampmodels.BusinessDaysAddResponse{}, err
+	}
+	start, instant, err := calendar.date(req.Date)
+	if err != nil {
+		return timestampmodels.BusinessDaysAddResponse{}, errors.New("invalid date: " + err.Error())
+	}
+
+	step, remaining := 1, req.Days
+	if remaining < 0 {
+		step, remaining = -1, -remaining
+	}
+	skipped := []timestampmodels.Holiday{}
+	result := start
+	for scanned := 0; remaining > 0; scanned++ {
+		if scanned == maxBusinessScanDays {
+			return timestampmodels.BusinessDaysAddResponse{}, errors.New("no result within " + strconv.Itoa(maxBusinessScanDays) + " calendar days, the calendar has too few working days")
+		}
+		result = result.AddDate(0, 0, step)
+		if calendar.isWeekend(result) {
+			continue
+		}
+		if holidays := calendar.holidaysOn(result); len(holidays) > 0 {
+			skipped = append(skipped, holidayModels(result, holidays)...)
+			continue
+		}
+		remaining--
+	}
+
+	// Keep the wall clock time of the start on the result date
+	local := instant.In(calendar.loc)
+	wall := result.Add(time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second + time.Duration(local.Nanosecond()))
+	resultTime, _, _ := LocalizeWallClock(wall, calendar.loc)
+
+	return timestampmodels.BusinessDaysAddResponse{
+		Date:         start.Format(time.DateOnly),
+		Days:         req.Days,
+		Result:       result.Format(time.DateOnly),
+		ResultTime:   resultTime.Format(time.RFC3339Nano),
+		Seconds:      resultTime.Unix(),
+		CalendarDays: int(result.Sub(start) / nominalDay),
+		Holidays:     skipped,
+		Warnings:     calendar.warnings(start, result),
+	}, nil
+}
+
+// CheckBusinessDay reports whether a date is a working day and the nearest working days around it
+func CheckBusinessDay(req timestampmodels.BusinessDayCheckRequest) (timestampmodels.BusinessDayCheckResponse, error) {
+	if err := req.Validate(); err != nil {
+		return timestampmodels.BusinessDayCheckResponse{}, err
+	}
+
+	calendar, err := newBusinessCalendar(req.BusinessCalendar)
+	if err != nil {
+		return timestampmodels.BusinessDayCheckResponse{}, err
+	}
+	date, _, err := calendar.date(req.Date)
+	if err != nil {
+		return timestampmodels.BusinessDayCheckResponse{}, errors.New("invalid date: " + err.Error())
+	}
+
+	response := timestampmodels.BusinessDayCheckResponse{
+		Date:       date.Format(time.DateOnly),
+		Weekday:    date.Weekday().String(),
+		WorkingDay: calendar.isWorkingDay(date),
+		Weekend:    calendar.isWeekend(date),
+		Holidays:   holidayModels(date, calendar.holidaysOn(date)),
+	}
+
+	// Every week has a working day, so only holidays can push the search far
+	previous, next := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+	for i := 0; i < timestampmodels.MaxBusinessDays && !calendar.isWorkingDay(previous); i++ {
+		previous = previous.AddDate(0, 0, -1)
+	}
+	for i := 0; i < timestampmodels.MaxBusinessDays && !calendar.isWorkingDay(next); i++ {
+		next = next.AddDate(0, 0, 1)
+	}
+	response.PreviousWorkingDay = previous.Format(time.DateOnly)
+	response.NextWorkingDay = next.Format(time.DateOnly)
+	response.Warnings = calendar.warnings(previous, next)
+
+	return response, nil
+}
@@ -0,0 +1,313 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	timestampmodels "konverter/internal/timestamp/models"
+)
+
+// Nominal lengths used when a calendar duration must be expressed in exact units
+const (
+	nominalDay   = 24 * time.Hour
+	nominalMonth = 2629746 * time.Second // 1/12 of the mean Gregorian year of 365.2425 days
+)
+
+// isoDurationPattern matches ISO 8601 durations such as "P1Y2M3DT4H5M6.5S" and "-P2W" (lowercased)
+var isoDurationPattern = regexp.MustCompile(`^([+-])?p(?:(\d+)y)?(?:(\d+)m)?(?:(\d+)w)?(?:(\d+)d)?(?:t(?:(\d+(?:[.,]\d+)?)h)?(?:(\d+(?:[.,]\d+)?)m)?(?:(\d+(?:[.,]\d+)?)s)?)?$`)
+
+// parseDuration parses a Go duration ("1h30m"), an ISO 8601 duration ("P1DT2H"), human text ("2 days 3 hours")
+// or a bare number in unit, and reports which of "go", "iso8601", "human" or "number" it was
+func parseDuration(input, unit string) (calendarDuration, string, error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return calendarDuration{}, "", errors.New("duration is required")
+	}
+
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		if unit == "" {
+			unit = "s"
+		}
+		if _, ok := durationUnits[strings.ToLower(unit)]; !ok {
+			return calendarDuration{}, "", errors.New("unknown duration unit: " + unit)
+		}
+		d, err := parseHumanDuration(s + " " + unit)
+		return d, "number", err
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return calendarDuration{clock: d}, "go", nil
+	}
+
+	if strings.HasPrefix(strings.TrimLeft(strings.ToLower(s), "+-"), "p") {
+		d, err := parseISODuration(s)
+		return d, "iso8601", err
+	}
+
+	// Human text, optionally signed as a whole: "-2 days"
+	negative := strings.HasPrefix(s, "-")
+	d, err := parseHumanDuration(strings.TrimLeft(s, "+-"))
+	if err != nil {
+		return calendarDuration{}, "", errors.New("unable to parse duration as Go, ISO 8601 or human text: " + err.Error())
+	}
+	if negative {
+		d = d.negate()
+	}
+	return d, "human", nil
+}
+
+// parseISODuration parses an ISO 8601 duration; only the hour, minute and second parts may be fractional
+func parseISODuration(input string) (calendarDuration, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	m := isoDurationPattern.FindStringSubmatch(s)
+	if m == nil || strings.HasSuffix(s, "p") || strings.HasSuffix(s, "t") {
+		return calendarDuration{}, errors.New("invalid ISO 8601 duration: " + input)
+	}
+
+	whole := func(part string, scale int) (int, error) {
+		if part == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n > math.MaxInt32/scale {
+			return 0, errors.New("ISO 8601 duration is out of range: " + input)
+		}
+		return n * scale, nil
+	}
+
+	var d calendarDuration
+	var err error
+	var years, months, weeks, days int
+	if years, err = whole(m[2], 12); err != nil {
+		return calendarDuration{}, err
+	}
+	if months, err = whole(m[3], 1); err != nil {
+		return calendarDuration{}, err
+	}
+	if weeks, err = whole(m[4], 7); err != nil {
+		return calendarDuration{}, err
+	}
+	if days, err = whole(m[5], 1); err != nil {
+		return calendarDuration{}, err
+	}
+	d.months, d.days = years+months, weeks+days
+
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		part, err := parseDecimalDuration(m[6+i], unit)
+		if err != nil {
+			return calendarDuration{}, errors.New("ISO 8601 duration is out of range: " + input)
+		}
+		if d.clock+part < d.clock {
+			return calendarDuration{}, errors.New("ISO 8601 duration is out of range: " + input)
+		}
+		d.clock += part
+	}
+
+	if m[1] == "-" {
+		d = d.negate()
+	}
+	return d, nil
+}
+
+// parseDecimalDuration parses a non-negative decimal number of unit exactly, accepting '.' or ',' as separator
+func parseDecimalDuration(s string, unit time.Duration) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	integer, fraction, _ := strings.Cut(strings.ReplaceAll(s, ",", "."), ".")
+	n, err := strconv.ParseInt(integer, 10, 64)
+	if err != nil || n > math.MaxInt64/int64(unit) {
+		return 0, errors.New("out of range")
+	}
+	d := time.Duration(n) * unit
+	if fraction != "" {
+		f, _ := strconv.ParseFloat("0."+fraction, 64)
+		d += time.Duration(math.Round(f * float64(unit)))
+	}
+	return d, nil
+}
+
+// durationComponents splits a calendar duration into years, months, days, hours, minutes, seconds and nanoseconds
+func durationComponents(d calendarDuration) timestampmodels.DurationComponents {
+	return timestampmodels.DurationComponents{
+		Years:       d.months / 12,
+		Months:      d.months % 12,
+		Days:        d.days,
+		Hours:       int64(d.clock / time.Hour),
+		Minutes:     int64(d.clock % time.Hour / time.Minute),
+		Seconds:     int64(d.clock % time.Minute / time.Second),
+		Nanoseconds: int64(d.clock % time.Second),
+	}
+}
+
+// formatISODuration formats a calendar duration as ISO 8601; a duration with no positive part gets a leading '-'
+func formatISODuration(d calendarDuration) string {
+	sign := ""
+	if d.months <= 0 && d.days <= 0 && d.clock <= 0 && (d.months != 0 || d.days != 0 || d.clock != 0) {
+		sign, d = "-", d.negate()
+	}
+	c := durationComponents(d)
+
+	var b strings.Builder
+	b.WriteString(sign + "P")
+	for _, part := range []struct {
+		value  int
+		suffix string
+	}{{c.Years, "Y"}, {c.Months, "M"}, {c.Days, "D"}} {
+		if part.value != 0 {
+			b.WriteString(strconv.Itoa(part.value) + part.suffix)
+		}
+	}
+	if d.clock != 0 {
+		b.WriteString("T")
+		if c.Hours != 0 {
+			b.WriteString(strconv.FormatInt(c.Hours, 10) + "H")
+		}
+		if c.Minutes != 0 {
+			b.WriteString(strconv.FormatInt(c.Minutes, 10) + "M")
+		}
+		if c.Seconds != 0 || c.Nanoseconds != 0 {
+			b.WriteString(formatSeconds(c.Seconds, c.Nanoseconds) + "S")
+		}
+	}
+	if b.Len() == len(sign)+1 {
+		b.WriteString("T0S")
+	}
+	return b.String()
+}
+
+// formatSeconds formats seconds and nanoseconds of the same sign as a decimal without trailing zeros
+func formatSeconds(seconds, nanoseconds int64) string {
+	if nanoseconds == 0 {
+		return strconv.FormatInt(seconds, 10)
+	}
+	sign := ""
+	if seconds < 0 || nanoseconds < 0 {
+		sign, seconds, nanoseconds = "-", -seconds, -nanoseconds
+	}
+	return sign + strconv.FormatInt(seconds, 10) + "." + strings.TrimRight(strconv.FormatInt(1000000000+nanoseconds, 10)[1:], "0")
+}
+
+// humanizeDuration renders a calendar duration as English text, e.g. "1 year, 2 months, 3 days, 4 hours"
+// A duration with no positive part is prefixed with "minus"
+func humanizeDuration(d calendarDuration) string {
+	sign := ""
+	if d.months <= 0 && d.days <= 0 && d.clock <= 0 && (d.months != 0 || d.days != 0 || d.clock != 0) {
+		sign, d = "minus ", d.negate()
+	}
+	c := durationComponents(d)
+	parts := []string{}
+	add := func(value int64, unit string) {
+		if value == 0 {
+			return
+		}
+		if value != 1 && value != -1 {
+			unit += "s"
+		}
+		parts = append(parts, strconv.FormatInt(value, 10)+" "+unit)
+	}
+	add(int64(c.Years), "year")
+	add(int64(c.Months), "month")
+	add(int64(c.Days), "day")
+	add(c.Hours, "hour")
+	add(c.Minutes, "minute")
+	if c.Nanoseconds != 0 {
+		parts = append(parts, formatSeconds(c.Seconds, c.Nanoseconds)+" seconds")
+	} else {
+		add(c.Seconds, "second")
+	}
+
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	return sign + strings.Join(parts, ", ")
+}
+
+// durationTotals expresses a duration of seconds plus nanoseconds in every unit
+func durationTotals(seconds float64, nanoseconds *int64) timestampmodels.DurationTotals {
+	return timestampmodels.DurationTotals{
+		Nanoseconds:  nanoseconds,
+		Microseconds: seconds * 1e6,
+		Milliseconds: seconds * 1e3,
+		Seconds:      seconds,
+		Minutes:      seconds / 60,
+		Hours:        seconds / 3600,
+		Days:         seconds / 86400,
+		Weeks:        seconds / 604800,
+	}
+}
+
+// nominalTotals expresses a calendar duration in every unit, using nominal day and month lengths for calendar parts
+func nominalTotals(d calendarDuration) timestampmodels.DurationTotals {
+	seconds := float64(d.months)*nominalMonth.Seconds() + float64(d.days)*nominalDay.Seconds() + d.clock.Seconds()
+	var nanoseconds *int64
+	if d.months == 0 && math.Abs(seconds) < 9e9 {
+		n := int64(d.days)*int64(nominalDay) + int64(d.clock)
+		nanoseconds = &n
+	}
+	return durationTotals(seconds, nanoseconds)
+}
+
+// elapsedTotals expresses the exact elapsed time from start to end in every unit
+func elapsedTotals(start, end time.Time) timestampmodels.DurationTotals {
+	seconds := end.Unix() - start.Unix()
+	nanos := int64(end.Nanosecond() - start.Nanosecond())
+	var nanoseconds *int64
+	if seconds > math.MinInt64/1000000000+1 && seconds < math.MaxInt64/1000000000-1 {
+		n := seconds*1000000000 + nanos
+		nanoseconds = &n
+	}
+	return durationTotals(float64(seconds)+float64(nanos)/1e9, nanoseconds)
+}
+
+// calendarDiff returns the calendar duration from start to end in loc as whole months, then whole days,
+// then elapsed time, such that addCalendar(start, diff, loc) equals end; end must not be before start
+func calendarDiff(start, end time.Time, loc *time.Location) calendarDuration {
+	s, e := start.In(loc), end.In(loc)
+
+	months := (e.Year()-s.Year())*12 + int(e.Month()) - int(s.Month())
+	for months > 0 && addCalendar(start, calendarDuration{months: months}, loc).After(end) {
+		months--
+	}
+	base := addCalendar(start, calendarDuration{months: months}, loc)
+
+	days := int(wallDate(end, loc).Sub(wallDate(base, loc)) / nominalDay)
+	for days > 0 && addCalendar(start, calendarDuration{months: months, days: days}, loc).After(end) {
+		days--
+	}
+	base = addCalendar(start, calendarDuration{months: months, days: days}, loc)
+
+	return calendarDuration{months: months, days: days, clock: end.Sub(base)}
+}
+
+// resolveDate parses a date string for duration arithmetic: any supported format, an ISO week or ordinal date,
+// or a relative expression against now; zone-less input is placed in loc
+// Ambiguous dates take the reading in order ("DMY", "MDY" or "YMD"), and are an error without one
+func resolveDate(dateString, order string, loc *time.Location) (time.Time, error) {
+	matches, err := tryParseDateFormats(dateString, loc)
+	if err != nil {
+		natural, naturalErr := parseNaturalDate(dateString, time.Now(), loc)
+		if naturalErr != nil {
+			return time.Time{}, naturalErr
+		}
+		matches = []parsedDate{natural}
+	}
+	chosen := 0
+	if len(matches) > 1 {
+		order = strings.ToUpper(order)
+		chosen = slices.IndexFunc(matches, func(m parsedDate) bool { return m.format.order == order })
+		if chosen < 0 {
+			return time.Time{}, ambiguousDateError(matches, "date_order")
+		}
+	}
+	if matches[chosen].format.zoned {
+		return matches[chosen].time, nil
+	}
+	t, _, _ := LocalizeWallClock(matches[chosen].time, loc)
+	return t, nil
+}
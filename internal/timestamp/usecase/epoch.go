@@ -0,0 +1,395 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// tai64Base is the TAI64 label of 1970-01-01 00:00:00 TAI
+	tai64Base = uint64(1) << 62
+	// gpsTAIOffset is the constant difference between TAI and GPS time in seconds
+	gpsTAIOffset = 19
+	// secondsPerWeek is used for GPS week numbers
+	secondsPerWeek = 604800
+	// ntpEraSeconds is the length of one NTP era (2^32 seconds)
+	ntpEraSeconds = int64(1) << 32
+)
+
+var (
+	unixEpoch     = time.Unix(0, 0).UTC()
+	windowsEpoch  = time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC)
+	dotnetEpoch   = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	cocoaEpoch    = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	gpsEpoch      = time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC)
+	ntpEpoch      = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+	excelEpoch    = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	lotusEpoch    = time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)
+	excel1904     = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	excelLeapBug  = time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC)
+	julianDayUnix = big.NewRat(4881175, 2) // JD 2440587.5 is 1970-01-01T00:00:00Z
+	mjdUnix       = big.NewRat(40587, 1)   // MJD 40587 is 1970-01-01T00:00:00Z
+
+	ntpHexPattern  = regexp.MustCompile(`^(?i)(0x)?([0-9a-f]{8})[.\s]?([0-9a-f]{8})$`)
+	tai64Pattern   = regexp.MustCompile(`^(?i)@?([0-9a-f]{16})([0-9a-f]{8})?([0-9a-f]{8})?$`)
+	gpsWeekPattern = regexp.MustCompile(`^([0-9]+)[:/ ]([0-9]+(\.[0-9]+)?)$`)
+)
+
+// leapSeconds lists when TAI-UTC changed; before 1972 the libtai convention of 10 seconds is used
+var leapSeconds = []struct {
+	since  time.Time
+	offset int64
+}{
+	{time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC), 10},
+	{time.Date(1972, 7, 1, 0, 0, 0, 0, time.UTC), 11},
+	{time.Date(1973, 1, 1, 0, 0, 0, 0, time.UTC), 12},
+	{time.Date(1974, 1, 1, 0, 0, 0, 0, time.UTC), 13},
+	{time.Date(1975, 1, 1, 0, 0, 0, 0, time.UTC), 14},
+	{time.Date(1976, 1, 1, 0, 0, 0, 0, time.UTC), 15},
+	{time.Date(1977, 1, 1, 0, 0, 0, 0, time.UTC), 16},
+	{time.Date(1978, 1, 1, 0, 0, 0, 0, time.UTC), 17},
+	{time.Date(1979, 1, 1, 0, 0, 0, 0, time.UTC), 18},
+	{time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC), 19},
+	{time.Date(1981, 7, 1, 0, 0, 0, 0, time.UTC), 20},
+	{time.Date(1982, 7, 1, 0, 0, 0, 0, time.UTC), 21},
+	{time.Date(1983, 7, 1, 0, 0, 0, 0, time.UTC), 22},
+	{time.Date(1985, 7, 1, 0, 0, 0, 0, time.UTC), 23},
+	{time.Date(1988, 1, 1, 0, 0, 0, 0, time.UTC), 24},
+	{time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), 25},
+	{time.Date(1991, 1, 1, 0, 0, 0, 0, time.UTC), 26},
+	{time.Date(1992, 7, 1, 0, 0, 0, 0, time.UTC), 27},
+	{time.Date(1993, 7, 1, 0, 0, 0, 0, time.UTC), 28},
+	{time.Date(1994, 7, 1, 0, 0, 0, 0, time.UTC), 29},
+	{time.Date(1996, 1, 1, 0, 0, 0, 0, time.UTC), 30},
+	{time.Date(1997, 7, 1, 0, 0, 0, 0, time.UTC), 31},
+	{time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC), 32},
+	{time.Date(2006, 1, 1, 0, 0, 0, 0, time.UTC), 33},
+	{time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), 34},
+	{time.Date(2012, 7, 1, 0, 0, 0, 0, time.UTC), 35},
+	{time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC), 36},
+	{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 37},
+}
+
+// epochFormat converts between a textual time encoding and an instant
+type epochFormat struct {
+	description string
+	parse       func(value string) (time.Time, error)
+	format      func(t time.Time) (string, bool)
+}
+
+// epochFormatNames lists the supported formats in display order
+var epochFormatNames = []string{
+	"unix", "unix_ms", "unix_us", "unix_ns",
+	"filetime", "ldap_generalized_time", "dotnet_ticks", "cocoa", "chrome",
+	"gps", "gps_week", "tai64n", "ntp",
+	"excel", "excel_1904", "julian_day", "modified_julian_day",
+}
+
+// epochAliases maps alternative names to the format they share an encoding with
+var epochAliases = map[string]string{
+	"ldap":      "filetime",
+	"ad":        "filetime",
+	"core_data": "cocoa",
+	"webkit":    "chrome",
+	"lotus":     "excel",
+	"tai64":     "tai64n",
+	"tai64na":   "tai64n",
+}
+
+var epochFormats = map[string]epochFormat{
+	"unix":     linearFormat("Seconds since 1970-01-01 UTC", unixEpoch, nil, int64(time.Second), 9),
+	"unix_ms":  linearFormat("Milliseconds since 1970-01-01 UTC", unixEpoch, nil, int64(time.Millisecond), 6),
+	"unix_us":  linearFormat("Microseconds since 1970-01-01 UTC", unixEpoch, nil, int64(time.Microsecond), 3),
+	"unix_ns":  linearFormat("Nanoseconds since 1970-01-01 UTC", unixEpoch, nil, int64(time.Nanosecond), 0),
+	"filetime": linearFormat("Windows FILETIME / LDAP / Active Directory: 100ns intervals since 1601-01-01 UTC", windowsEpoch, nil, 100, 0),
+	"ldap_generalized_time": {
+		description: "LDAP GeneralizedTime (e.g., 20240101120000.0Z)",
+		parse:       parseGeneralizedTime,
+		format:      formatGeneralizedTime,
+	},
+	"dotnet_ticks": linearFormat(".NET DateTime ticks: 100ns intervals since 0001-01-01", dotnetEpoch, nil, 100, 0),
+	"cocoa":        linearFormat("Apple Cocoa / Core Data: seconds since 2001-01-01 UTC", cocoaEpoch, nil, int64(time.Second), 9),
+	"chrome":       linearFormat("Chrome / WebKit: microseconds since 1601-01-01 UTC", windowsEpoch, nil, int64(time.Microsecond), 3),
+	"gps": {
+		description: "GPS time: seconds since 1980-01-06, without leap seconds",
+		parse:       parseGPS,
+		format:      formatGPS,
+	},
+	"gps_week": {
+		description: "GPS week and seconds of week (e.g., 2295:345600)",
+		parse:       parseGPS,
+		format:      formatGPSWeek,
+	},
+	"tai64n": {
+		description: "TAI64 / TAI64N / TAI64NA external label, hex with optional '@'",
+		parse:       parseTAI64,
+		format:      formatTAI64N,
+	},
+	"ntp": {
+		description: "NTP 64-bit timestamp: 32.32 fixed point seconds since 1900-01-01, as hex or decimal seconds",
+		parse:       parseNTP,
+		format:      formatNTP,
+	},
+	"excel": {
+		description: "Excel / Lotus 1-2-3 serial date (1900 system, includes the 1900-02-29 bug)",
+		parse:       parseExcel,
+		format:      formatExcel,
+	},
+	"excel_1904":          linearFormat("Excel serial date, 1904 system (days since 1904-01-01)", excel1904, nil, int64(24*time.Hour), 10),
+	"julian_day":          linearFormat("Julian Day number", unixEpoch, julianDayUnix, int64(24*time.Hour), 10),
+	"modified_julian_day": linearFormat("Modified Julian Day (JD - 2400000.5)", unixEpoch, mjdUnix, int64(24*time.Hour), 10),
+}
+
+// linearFormat builds a format where value = refValue + (t - ref) / unit
+// decimals is the number of fractional digits used on output
+func linearFormat(description string, ref time.Time, refValue *big.Rat, unitNanos int64, decimals int) epochFormat {
+	if refValue == nil {
+		refValue = new(big.Rat)
+	}
+	return epochFormat{
+		description: description,
+		parse: func(value string) (time.Time, error) {
+			v, ok := new(big.Rat).SetString(value)
+			if !ok {
+				return time.Time{}, errors.New("value must be a number")
+			}
+			nanos := new(big.Rat).Sub(v, refValue)
+			nanos.Mul(nanos, new(big.Rat).SetInt64(unitNanos))
+			return addNanos(ref, nanos)
+		},
+		format: func(t time.Time) (string, bool) {
+			value := new(big.Rat).SetFrac(nanosBetween(ref, t), big.NewInt(unitNanos))
+			value.Add(value, refValue)
+			return formatRat(value, decimals), true
+		},
+	}
+}
+
+// addNanos adds a (possibly fractional, possibly huge) number of nanoseconds to t, flooring to whole nanoseconds
+func addNanos(t time.Time, nanos *big.Rat) (time.Time, error) {
+	// Euclidean division with a positive divisor floors towards negative infinity
+	whole := new(big.Int).Div(nanos.Num(), nanos.Denom())
+	seconds, nsec := new(big.Int).DivMod(whole, big.NewInt(int64(time.Second)), new(big.Int))
+	seconds.Add(seconds, big.NewInt(t.Unix()))
+	if !seconds.IsInt64() || !inUnixRange(seconds.Int64()) {
+		return time.Time{}, errors.New("value is outside the representable time range")
+	}
+	return time.Unix(seconds.Int64(), nsec.Int64()+int64(t.Nanosecond())).UTC(), nil
+}
+
+// nanosBetween returns t - ref in nanoseconds without overflowing
+func nanosBetween(ref, t time.Time) *big.Int {
+	nanos := big.NewInt(t.Unix() - ref.Unix())
+	nanos.Mul(nanos, big.NewInt(int64(time.Second)))
+	return nanos.Add(nanos, big.NewInt(int64(t.Nanosecond()-ref.Nanosecond())))
+}
+
+// formatRat prints a rational number with up to decimals fractional digits, trimming trailing zeros
+func formatRat(value *big.Rat, decimals int) string {
+	if decimals == 0 {
+		// Floor so integer formats never round into the next unit
+		return new(big.Int).Div(value.Num(), value.Denom()).String()
+	}
+	s := value.FloatString(decimals)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+// taiMinusUTC returns TAI-UTC in seconds at the given UTC instant
+func taiMinusUTC(utc time.Time) int64 {
+	offset := leapSeconds[0].offset
+	for _, leap := range leapSeconds {
+		if utc.Before(leap.since) {
+			break
+		}
+		offset = leap.offset
+	}
+	return offset
+}
+
+// utcFromTAI converts an instant counted on the TAI scale (as if it were UTC) to UTC
+func utcFromTAI(tai time.Time) time.Time {
+	for i := len(leapSeconds) - 1; i >= 0; i-- {
+		utc := tai.Add(-time.Duration(leapSeconds[i].offset) * time.Second)
+		if !utc.Before(leapSeconds[i].since) {
+			return utc
+		}
+	}
+	return tai.Add(-time.Duration(leapSeconds[0].offset) * time.Second)
+}
+
+// parseGPS parses GPS seconds, or "week:seconds-of-week", and applies leap seconds
+func parseGPS(value string) (time.Time, error) {
+	seconds, ok := new(big.Rat).SetString(value)
+	if !ok {
+		match := gpsWeekPattern.FindStringSubmatch(value)
+		if match == nil {
+			return time.Time{}, errors.New("value must be GPS seconds or 'week:seconds'")
+		}
+		week, _ := new(big.Rat).SetString(match[1])
+		seconds, _ = new(big.Rat).SetString(match[2])
+		seconds.Add(seconds, week.Mul(week, big.NewRat(secondsPerWeek, 1)))
+	}
+
+	// GPS time runs ahead of UTC by the leap seconds added since 1980
+	gps, err := addNanos(gpsEpoch, seconds.Mul(seconds, big.NewRat(int64(time.Second), 1)))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return utcFromTAI(gps.Add(gpsTAIOffset * time.Second)), nil
+}
+
+// gpsSeconds returns the GPS time of a UTC instant as a rational number of seconds
+func gpsSeconds(t time.Time) *big.Rat {
+	gps := t.Add(time.Duration(taiMinusUTC(t)-gpsTAIOffset) * time.Second)
+	return new(big.Rat).SetFrac(nanosBetween(gpsEpoch, gps), big.NewInt(int64(time.Second)))
+}
+
+func formatGPS(t time.Time) (string, bool) {
+	return formatRat(gpsSeconds(t), 9), true
+}
+
+func formatGPSWeek(t time.Time) (string, bool) {
+	seconds := gpsSeconds(t)
+	if seconds.Sign() < 0 {
+		return "", false
+	}
+	week := new(big.Int).Div(seconds.Num(), new(big.Int).Mul(seconds.Denom(), big.NewInt(secondsPerWeek)))
+	rest := new(big.Rat).Sub(seconds, new(big.Rat).SetInt(new(big.Int).Mul(week, big.NewInt(secondsPerWeek))))
+	return week.String() + ":" + formatRat(rest, 9), true
+}
+
+// parseTAI64 parses a TAI64, TAI64N or TAI64NA label; attoseconds are truncated to nanoseconds
+func parseTAI64(value string) (time.Time, error) {
+	match := tai64Pattern.FindStringSubmatch(value)
+	if match == nil {
+		return time.Time{}, errors.New("value must be a TAI64 label of 16, 24 or 32 hex digits")
+	}
+	label, _ := strconv.ParseUint(match[1], 16, 64)
+	if label >= 1<<63 {
+		return time.Time{}, errors.New("TAI64 labels with the top bit set are reserved")
+	}
+	var nanos uint64
+	if match[2] != "" {
+		nanos, _ = strconv.ParseUint(match[2], 16, 32)
+		if nanos >= uint64(time.Second) {
+			return time.Time{}, errors.New("TAI64N nanoseconds must be below 1000000000")
+		}
+	}
+
+	taiSeconds := int64(label) - int64(tai64Base)
+	if !inUnixRange(taiSeconds) {
+		return time.Time{}, errors.New("value is outside the representable time range")
+	}
+	return utcFromTAI(time.Unix(taiSeconds, int64(nanos)).UTC()), nil
+}
+
+func formatTAI64N(t time.Time) (string, bool) {
+	tai := t.Add(time.Duration(taiMinusUTC(t)) * time.Second)
+	return fmt.Sprintf("@%016x%08x", uint64(tai.Unix())+tai64Base, tai.Nanosecond()), true
+}
+
+// parseNTP parses an NTP timestamp given as 64-bit hex (optionally "seconds.fraction" hex) or decimal seconds
+// Seconds with the top bit clear are taken to be in era 1 (2036-2104), per RFC 4330
+func parseNTP(value string) (time.Time, error) {
+	var seconds int64
+	var fraction uint64
+
+	if match := ntpHexPattern.FindStringSubmatch(value); match != nil && (match[1] != "" || strings.ContainsAny(strings.ToLower(value), "abcdef")) {
+		s, _ := strconv.ParseUint(match[2], 16, 32)
+		fraction, _ = strconv.ParseUint(match[3], 16, 32)
+		seconds = int64(s)
+	} else if raw, err := strconv.ParseUint(value, 10, 64); err == nil && raw > math.MaxUint32 {
+		// A bare integer too large for 32 bits is the raw 64-bit fixed-point value
+		seconds = int64(raw >> 32)
+		fraction = raw & math.MaxUint32
+	} else {
+		v, ok := new(big.Rat).SetString(value)
+		if !ok || v.Sign() < 0 {
+			return time.Time{}, errors.New("value must be 64-bit hex (0x...) or decimal NTP seconds")
+		}
+		return addNanos(ntpEpoch, v.Mul(v, big.NewRat(int64(time.Second), 1)))
+	}
+
+	if seconds < 1<<31 {
+		seconds += ntpEraSeconds
+	}
+	nanos := int64((fraction*uint64(time.Second) + (1 << 31)) >> 32)
+	return time.Unix(ntpEpoch.Unix()+seconds, nanos).UTC(), nil
+}
+
+// formatNTP prints the raw 64-bit NTP value as hex; only instants from 1968 to 2104 can be encoded
+func formatNTP(t time.Time) (string, bool) {
+	seconds := t.Unix() - ntpEpoch.Unix()
+	if seconds < 1<<31 || seconds >= ntpEraSeconds+(1<<31) {
+		return "", false
+	}
+	fraction := (uint64(t.Nanosecond())<<32 + uint64(time.Second)/2) / uint64(time.Second)
+	return fmt.Sprintf("0x%08x%08x", uint32(seconds), fraction), true
+}
+
+// parseExcel parses a 1900-system serial date, which wrongly counts 1900-02-29 as serial 60
+func parseExcel(value string) (time.Time, error) {
+	v, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return time.Time{}, errors.New("value must be a number")
+	}
+	day := big.NewRat(int64(24*time.Hour), 1)
+	switch {
+	case v.Cmp(big.NewRat(61, 1)) >= 0:
+		return addNanos(excelEpoch, v.Mul(v, day))
+	case v.Cmp(big.NewRat(60, 1)) >= 0:
+		return time.Time{}, errors.New("serial 60 is 1900-02-29, which does not exist (Lotus 1-2-3 leap year bug)")
+	default:
+		return addNanos(lotusEpoch, v.Mul(v, day))
+	}
+}
+
+func formatExcel(t time.Time) (string, bool) {
+	ref := excelEpoch
+	if t.Before(excelLeapBug) {
+		ref = lotusEpoch
+	}
+	value := new(big.Rat).SetFrac(nanosBetween(ref, t), big.NewInt(int64(24*time.Hour)))
+	return formatRat(value, 10), true
+}
+
+// parseGeneralizedTime parses LDAP GeneralizedTime with optional fraction and zone offset
+func parseGeneralizedTime(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102150405.999999999Z0700", "20060102150405Z0700", "200601021504Z0700"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("value must be GeneralizedTime such as 20240101120000.0Z")
+}
+
+// formatGeneralizedTime prints GeneralizedTime in UTC the way Active Directory does, keeping any fraction
+func formatGeneralizedTime(t time.Time) (string, bool) {
+	s := t.UTC().Format("20060102150405.999999999")
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s + "Z", true
+}
+
+// resolveEpochFormat returns the canonical name and definition of a format or alias
+func resolveEpochFormat(name string) (string, epochFormat, bool) {
+	name = strings.ToLower(name)
+	if alias, ok := epochAliases[name]; ok {
+		name = alias
+	}
+	format, ok := epochFormats[name]
+	return name, format, ok
+}
@@ -2,81 +2,157 @@ package usecase
 
 import (
 	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// Determines the unit of the timestamp based on its magnitude
-func detectUnit(timestamp int64) string {
-	if timestamp < 10000000000 { // 10^10
+// maxUnixSeconds is the largest Unix second count time.Time can hold without overflowing
+const maxUnixSeconds = math.MaxInt64 - 62135596800
+
+// minUnixSeconds is the smallest Unix second count time.Time can format without wrapping: March 1 of year
+// -292277022400, the start of the absolute calendar time.Time computes dates from
+const minUnixSeconds = -(62135596800 + (292277022400*3652425/10000+306)*86400)
+
+// inUnixRange reports whether a Unix second count lies between minUnixSeconds and maxUnixSeconds
+func inUnixRange(seconds int64) bool {
+	return seconds >= minUnixSeconds && seconds <= maxUnixSeconds
+}
+
+// rawSentinels are special values recognized by the raw input number, regardless of unit
+var rawSentinels = []struct {
+	value int64
+	name  string
+}{
+	{-1, "-1 (common 'unset' or error value)"},
+	{math.MaxInt32, "max signed 32-bit time_t (year 2038 problem)"},
+	{math.MinInt32, "min signed 32-bit time_t"},
+	{math.MaxUint32, "max unsigned 32-bit time_t"},
+	{math.MaxInt64, "max int64"},
+	{math.MinInt64, "min int64"},
+}
+
+// instantSentinels are special values recognized by the instant they represent
+var instantSentinels = []struct {
+	instant time.Time
+	name    string
+}{
+	{time.Unix(0, 0), "Unix epoch"},
+	{time.Time{}, "Go zero time / .NET DateTime.MinValue (0001-01-01)"},
+	{time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC), "Windows FILETIME epoch (1601-01-01)"},
+	{time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), "NTP epoch (1900-01-01)"},
+	{time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC), "GPS epoch (1980-01-06)"},
+	{time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC), "Apple Cocoa epoch (2001-01-01)"},
+	{time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC), "max 4-digit year (9999-12-31T23:59:59Z)"},
+	{time.UnixMilli(8640000000000000), "max JavaScript Date"},
+	{time.UnixMilli(-8640000000000000), "min JavaScript Date"},
+}
+
+// DetectUnit determines the unit of the timestamp based on its magnitude; negative values use their absolute value
+func DetectUnit(timestamp int64) string {
+	// Compare as unsigned so math.MinInt64 doesn't overflow on negation
+	magnitude := uint64(timestamp)
+	if timestamp < 0 {
+		magnitude = -magnitude
+	}
+
+	if magnitude < 10000000000 { // 10^10
 		return "seconds"
-	} else if timestamp < 10000000000000 { // 10^13
+	} else if magnitude < 10000000000000 { // 10^13
 		return "milliseconds"
+	} else if magnitude < 10000000000000000 { // 10^16
+		return "microseconds"
 	}
-	return "microseconds"
+	return "nanoseconds"
 }
 
-// Converts any timestamp unit to seconds
-func normalizeToSeconds(timestamp int64, unit string) int64 {
+// timeFromUnit builds a time from a timestamp in the given unit, keeping sub-second precision
+func timeFromUnit(timestamp int64, unit string) (time.Time, error) {
 	switch unit {
-	case "seconds":
-		return timestamp
 	case "milliseconds":
-		return timestamp / 1000
+		return time.UnixMilli(timestamp), nil
 	case "microseconds":
-		return timestamp / 1000000
+		return time.UnixMicro(timestamp), nil
 	case "nanoseconds":
-		return timestamp / 1000000000
+		return time.Unix(0, timestamp), nil
 	default:
-		return timestamp
+		if timestamp > maxUnixSeconds {
+			return time.Time{}, errors.New("timestamp is beyond the largest representable time")
+		}
+		if timestamp < minUnixSeconds {
+			return time.Time{}, errors.New("timestamp is beyond the smallest representable time")
+		}
+		return time.Unix(timestamp, 0), nil
 	}
 }
 
-// Converts any timestamp unit to milliseconds
-func normalizeToMilliseconds(timestamp int64, unit string) int64 {
-	switch unit {
-	case "seconds":
-		return timestamp * 1000
-	case "milliseconds":
-		return timestamp
-	case "microseconds":
-		return timestamp / 1000
-	case "nanoseconds":
-		return timestamp / 1000000000
-	default:
-		return timestamp
+// unixIn converts a time to a Unix timestamp counted in units of 1/perSecond seconds,
+// flooring sub-unit remainders; ok is false when the result doesn't fit in an int64
+func unixIn(t time.Time, perSecond int64) (value int64, ok bool) {
+	seconds := t.Unix()
+	remainder := int64(t.Nanosecond()) / (1000000000 / perSecond)
+	// Borrow a second for negative times so values just inside the int64 minimum don't overflow midway
+	if seconds < 0 && remainder > 0 {
+		seconds++
+		remainder -= perSecond
 	}
+
+	scaled := seconds * perSecond
+	if seconds != 0 && scaled/perSecond != seconds {
+		return 0, false
+	}
+	if (remainder > 0 && scaled > math.MaxInt64-remainder) || (remainder < 0 && scaled < math.MinInt64-remainder) {
+		return 0, false
+	}
+	return scaled + remainder, true
 }
 
-// Converts any timestamp unit to microseconds
-func normalizeToMicroseconds(timestamp int64, unit string) int64 {
-	switch unit {
-	case "seconds":
-		return timestamp * 1000000
-	case "milliseconds":
-		return timestamp * 1000
-	case "microseconds":
-		return timestamp
-	case "nanoseconds":
-		return timestamp / 1000000
-	default:
-		return timestamp
+// unixInPtr is unixIn returning nil when the value overflows, for optional response fields
+func unixInPtr(t time.Time, perSecond int64) *int64 {
+	value, ok := unixIn(t, perSecond)
+	if !ok {
+		return nil
 	}
+	return &value
 }
 
-// Converts any timestamp unit to nanoseconds
-func normalizeToNanoseconds(timestamp int64, unit string) int64 {
-	switch unit {
-	case "seconds":
-		return timestamp * 1000000000
-	case "milliseconds":
-		return timestamp * 1000000
-	case "microseconds":
-		return timestamp * 1000
-	case "nanoseconds":
-		return timestamp / 1000
-	default:
-		return timestamp
+// findSentinels lists the well-known special values the timestamp corresponds to
+// Instants are also checked with the other units, since magnitude-based detection is ambiguous for them
+func findSentinels(timestamp int64, unit string) []string {
+	sentinels := []string{}
+	for _, sentinel := range rawSentinels {
+		if sentinel.value == timestamp {
+			sentinels = append(sentinels, sentinel.name)
+		}
 	}
+
+	units := []string{unit, "seconds", "milliseconds", "microseconds", "nanoseconds"}
+	for i, u := range units {
+		if i > 0 && u == unit {
+			continue
+		}
+		t, err := timeFromUnit(timestamp, u)
+		if err != nil {
+			continue
+		}
+		for _, sentinel := range instantSentinels {
+			if !sentinel.instant.Equal(t) {
+				continue
+			}
+			name := sentinel.name
+			if u != unit {
+				name += ", read as " + u
+			}
+			// Zero is the epoch in every unit, so only report it once
+			if u != unit && timestamp == 0 {
+				continue
+			}
+			sentinels = append(sentinels, name)
+		}
+	}
+	return sentinels
 }
 
 // formatTimezoneTime formats the time in the specified timezone
@@ -88,75 +164,235 @@ func formatTimezoneTime(t time.Time, timezone string) (string, error) {
 	}
 
 	// Convert to the specified timezone and format
-	return t.In(loc).Format(time.RFC3339), nil
+	return t.In(loc).Format(time.RFC3339Nano), nil
+}
+
+// dateFormat is a parse layout with a display name
+type dateFormat struct {
+	layout string
+	name   string
+	// zoned is set when the layout carries its own zone (offset, abbreviation or a literal Z)
+	zoned bool
+	// utc is set when the layout ends in a literal Z, meaning the input is always UTC
+	utc bool
+	// order is the day/month/year order of numeric dates ("DMY", "MDY" or "YMD"), empty when the layout fixes it
+	order string
+}
+
+// parsedDate is one interpretation of a date string
+type parsedDate struct {
+	time   time.Time
+	format dateFormat
+}
+
+// dateFormats lists common date formats in order of preference
+var dateFormats = []dateFormat{
+	// RFC formats
+	{time.RFC3339, "RFC3339", true, false, ""},
+	{time.RFC3339Nano, "RFC3339Nano", true, false, ""},
+	{time.RFC1123, "RFC1123", true, false, ""},
+	{time.RFC1123Z, "RFC1123Z", true, false, ""},
+	{time.RFC822, "RFC822", true, false, ""},
+	{time.RFC822Z, "RFC822Z", true, false, ""},
+	{time.RFC850, "RFC850", true, false, ""},
+
+	// ISO formats
+	{"2006-01-02T15:04:05Z", "ISO8601", true, true, ""},
+	{"2006-01-02T15:04:05.000Z", "ISO8601_milliseconds", true, true, ""},
+	{"2006-01-02T15:04:05.000000Z", "ISO8601_microseconds", true, true, ""},
+	{"2006-01-02T15:04:05.000000000Z", "ISO8601_nanoseconds", true, true, ""},
+
+	// Date only formats
+	{"2006-01-02", "Y-M-D", false, false, "YMD"},
+	{"2006/01/02", "Y/M/D", false, false, "YMD"},
+	{"02-01-2006", "D-M-Y", false, false, "DMY"},
+	{"02/01/2006", "D/M/Y", false, false, "DMY"},
+	{"01-02-2006", "M-D-Y", false, false, "MDY"},
+	{"01/02/2006", "M/D/Y", false, false, "MDY"},
+	{"2006-1-2", "Y-M-D_single_digit", false, false, "YMD"},
+	{"2006/1/2", "Y/M/D_single_digit", false, false, "YMD"},
+	{"2-1-2006", "D-M-Y_single_digit", false, false, "DMY"},
+	{"2/1/2006", "D/M/Y_single_digit", false, false, "DMY"},
+	{"1-2-2006", "M-D-Y_single_digit", false, false, "MDY"},
+	{"1/2/2006", "M/D/Y_single_digit", false, false, "MDY"},
+
+	// Date with time formats
+	{"2006-01-02 15:04:05", "Y-M-D H:M:S", false, false, "YMD"},
+	{"2006/01/02 15:04:05", "Y/M/D H:M:S", false, false, "YMD"},
+	{"02-01-2006 15:04:05", "D-M-Y H:M:S", false, false, "DMY"},
+	{"02/01/2006 15:04:05", "D/M/Y H:M:S", false, false, "DMY"},
+	{"01-02-2006 15:04:05", "M-D-Y H:M:S", false, false, "MDY"},
+	{"01/02/2006 15:04:05", "M/D/Y H:M:S", false, false, "MDY"},
+
+	// Date with time and timezone
+	{"2006-01-02 15:04:05 MST", "Y-M-D H:M:S MST", true, false, "YMD"},
+	{"2006/01/02 15:04:05 MST", "Y/M/D H:M:S MST", true, false, "YMD"},
+	{"02-01-2006 15:04:05 MST", "D-M-Y H:M:S MST", true, false, "DMY"},
+	{"02/01/2006 15:04:05 MST", "D/M/Y H:M:S MST", true, false, "DMY"},
+	{"01-02-2006 15:04:05 MST", "M-D-Y H:M:S MST", true, false, "MDY"},
+	{"01/02/2006 15:04:05 MST", "M/D/Y H:M:S MST", true, false, "MDY"},
 }
 
 // tryParseDateFormats attempts to parse a date string using multiple format layouts
-// Returns the parsed time and the format name that matched, or an error if no format matches
-func tryParseDateFormats(dateString string) (time.Time, string, error) {
+// Zone abbreviations are resolved against loc when they match it; zone-less input is returned
+// as a wall clock in UTC so the caller can place it in a location with LocalizeWallClock
+// Returns every distinct interpretation in format preference order, or an error if no format matches
+func tryParseDateFormats(dateString string, loc *time.Location) ([]parsedDate, error) {
 	// Strip "GMT" prefix if present to enable local timezone parsing
 	cleanedString := dateString
 	if len(dateString) > 3 && dateString[:3] == "GMT" {
 		cleanedString = dateString[3:]
 	}
 
-	// Define common date formats in order of preference
-	formats := []struct {
-		layout string
-		name   string
-	}{
-		// RFC formats
-		{time.RFC3339, "RFC3339"},
-		{time.RFC3339Nano, "RFC3339Nano"},
-		{time.RFC1123, "RFC1123"},
-		{time.RFC1123Z, "RFC1123Z"},
-		{time.RFC822, "RFC822"},
-		{time.RFC822Z, "RFC822Z"},
-		{time.RFC850, "RFC850"},
-
-		// ISO formats
-		{"2006-01-02T15:04:05Z", "ISO8601"},
-		{"2006-01-02T15:04:05.000Z", "ISO8601_milliseconds"},
-		{"2006-01-02T15:04:05.000000Z", "ISO8601_microseconds"},
-		{"2006-01-02T15:04:05.000000000Z", "ISO8601_nanoseconds"},
-
-		// Date only formats
-		{"2006-01-02", "Y-M-D"},
-		{"2006/01/02", "Y/M/D"},
-		{"02-01-2006", "D-M-Y"},
-		{"02/01/2006", "D/M/Y"},
-		{"01-02-2006", "M-D-Y"},
-		{"01/02/2006", "M/D/Y"},
-		{"2006-1-2", "Y-M-D_single_digit"},
-		{"2006/1/2", "Y/M/D_single_digit"},
-		{"2-1-2006", "D-M-Y_single_digit"},
-		{"2/1/2006", "D/M/Y_single_digit"},
-		{"1-2-2006", "M-D-Y_single_digit"},
-		{"1/2/2006", "M/D/Y_single_digit"},
-
-		// Date with time formats
-		{"2006-01-02 15:04:05", "Y-M-D H:M:S"},
-		{"2006/01/02 15:04:05", "Y/M/D H:M:S"},
-		{"02-01-2006 15:04:05", "D-M-Y H:M:S"},
-		{"02/01/2006 15:04:05", "D/M/Y H:M:S"},
-		{"01-02-2006 15:04:05", "M-D-Y H:M:S"},
-		{"01/02/2006 15:04:05", "M/D/Y H:M:S"},
-
-		// Date with time and timezone
-		{"2006-01-02 15:04:05 MST", "Y-M-D H:M:S MST"},
-		{"2006/01/02 15:04:05 MST", "Y/M/D H:M:S MST"},
-		{"02-01-2006 15:04:05 MST", "D-M-Y H:M:S MST"},
-		{"02/01/2006 15:04:05 MST", "D/M/Y H:M:S MST"},
-		{"01-02-2006 15:04:05 MST", "M-D-Y H:M:S MST"},
-		{"01/02/2006 15:04:05 MST", "M/D/Y H:M:S MST"},
-	}
-
-	// Try each format
-	for _, format := range formats {
-		if t, err := time.Parse(format.layout, cleanedString); err == nil {
-			return t, format.name, nil
+	// Try each format, keeping one match per distinct result
+	matches := []parsedDate{}
+	for _, format := range dateFormats {
+		parseLoc := loc
+		if format.utc || !format.zoned {
+			parseLoc = time.UTC
 		}
+		t, err := time.ParseInLocation(format.layout, cleanedString, parseLoc)
+		if err != nil {
+			continue
+		}
+		if !slices.ContainsFunc(matches, func(m parsedDate) bool { return m.time.Equal(t) }) {
+			matches = append(matches, parsedDate{time: t, format: format})
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, errors.New("unable to parse date string with any supported format")
+	}
+	return matches, nil
+}
+
+// parseReferenceTime parses the reference time for relative expressions: Unix seconds or any supported
+// date string, with zone-less strings placed in loc; empty means now
+func parseReferenceTime(reference string, loc *time.Location) (time.Time, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return time.Now(), nil
+	}
+	if seconds, err := strconv.ParseInt(reference, 10, 64); err == nil {
+		return timeFromUnit(seconds, "seconds")
+	}
+
+	matches, err := tryParseDateFormats(reference, loc)
+	if err != nil {
+		return time.Time{}, errors.New("invalid reference time: " + err.Error())
+	}
+	if matches[0].format.zoned {
+		return matches[0].time, nil
+	}
+	t, _, _ := LocalizeWallClock(matches[0].time, loc)
+	return t, nil
+}
+
+// localeDateOrders maps locales and languages to their customary numeric date order
+var localeDateOrders = map[string]string{
+	"en": "MDY", "en-us": "MDY", "en-ph": "MDY", "en-ca": "YMD",
+	"en-gb": "DMY", "en-au": "DMY", "en-nz": "DMY", "en-ie": "DMY", "en-in": "DMY", "en-za": "YMD", "en-sg": "DMY",
+	"fr": "DMY", "de": "DMY", "es": "DMY", "it": "DMY", "pt": "DMY", "nl": "DMY", "ru": "DMY", "uk": "DMY",
+	"pl": "DMY", "cs": "DMY", "da": "DMY", "nb": "DMY", "fi": "DMY", "el": "DMY", "ro": "DMY", "tr": "DMY",
+	"vi": "DMY", "id": "DMY", "th": "DMY", "ar": "DMY", "he": "DMY", "hi": "DMY",
+	"ja": "YMD", "zh": "YMD", "ko": "YMD", "hu": "YMD", "lt": "YMD", "sv": "YMD", "mn": "YMD",
+}
+
+// resolveDateOrder returns the preferred numeric date order from an explicit order or a locale
+// An explicit order wins; locales are matched by full tag, then by language
+func resolveDateOrder(order, locale string) (string, error) {
+	if order != "" || locale == "" {
+		return strings.ToUpper(order), nil
+	}
+	tag := strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if order, ok := localeDateOrders[tag]; ok {
+		return order, nil
+	}
+	language, _, _ := strings.Cut(tag, "-")
+	if order, ok := localeDateOrders[language]; ok {
+		return order, nil
 	}
+	return "", errors.New("unsupported locale: " + locale + ", set date_order instead")
+}
+
+// ambiguousDateError lists every reading of an ambiguous date string, suggesting the fields that settle it
+func ambiguousDateError(matches []parsedDate, fields string) error {
+	readings := []string{}
+	for _, m := range matches {
+		readings = append(readings, m.time.Format("2006-01-02")+" ("+m.format.name+")")
+	}
+	return errors.New("ambiguous date string, could be " + strings.Join(readings, " or ") + "; set " + fields)
+}
+
+// FindZoneTransition returns the first instant at or after before that has after's UTC offset in loc,
+// to the second; before and after must have different offsets with one transition between them
+func FindZoneTransition(before, after time.Time, loc *time.Location) time.Time {
+	_, target := after.In(loc).Zone()
+	lo, hi := before.Unix(), after.Unix()
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if _, offset := time.Unix(mid, 0).In(loc).Zone(); offset == target {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return time.Unix(hi, 0).In(loc)
+}
+
+// LocalizeWallClock places a wall clock held in UTC into loc
+// The status is "unique", "gap" (skipped by a DST change; the time is shifted forward) or "ambiguous"
+// (repeated; the earlier instant is used), and candidates lists both instants for the latter two
+func LocalizeWallClock(wall time.Time, loc *time.Location) (time.Time, string, []time.Time) {
+	candidates := resolveLocalTime(wall, loc)
+	switch len(candidates) {
+	case 0:
+		candidates = gapCandidates(wall, loc)
+		return candidates[0], "gap", candidates
+	case 1:
+		return candidates[0], "unique", nil
+	}
+	return candidates[0], "ambiguous", candidates
+}
+
+// resolveLocalTime finds every instant whose wall clock in loc matches wall, a wall clock held in UTC
+// It returns one instant normally, none inside a DST gap, and two inside a DST overlap
+func resolveLocalTime(wall time.Time, loc *time.Location) []time.Time {
+	// Treat the wall clock as if it were UTC, then try each offset in effect around that day
+	offsets := []int{}
+	for _, probe := range []time.Duration{-48 * time.Hour, 0, 48 * time.Hour} {
+		_, offset := wall.Add(probe).In(loc).Zone()
+		if !slices.Contains(offsets, offset) {
+			offsets = append(offsets, offset)
+		}
+	}
+
+	instants := []time.Time{}
+	for _, offset := range offsets {
+		candidate := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if sameWallClock(candidate, wall) && !slices.ContainsFunc(instants, candidate.Equal) {
+			instants = append(instants, candidate)
+		}
+	}
+	slices.SortFunc(instants, func(a, b time.Time) int { return a.Compare(b) })
+	return instants
+}
+
+// gapCandidates interprets a wall clock that falls in a DST gap with the offsets before and after the gap
+// The first result is the wall clock shifted forward by the gap length
+func gapCandidates(wall time.Time, loc *time.Location) []time.Time {
+	_, before := wall.Add(-48 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(48 * time.Hour).In(loc).Zone()
+	return []time.Time{
+		wall.Add(-time.Duration(before) * time.Second).In(loc),
+		wall.Add(-time.Duration(after) * time.Second).In(loc),
+	}
+}
 
-	return time.Time{}, "", errors.New("unable to parse date string with any supported format")
+// sameWallClock reports whether a and b show the same date and time of day, ignoring zones
+func sameWallClock(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd && a.Hour() == b.Hour() && a.Minute() == b.Minute() &&
+		a.Second() == b.Second() && a.Nanosecond() == b.Nanosecond()
 }
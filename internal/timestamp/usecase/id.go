@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base62Alphabet    = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// ksuidEpoch is the KSUID epoch (2014-05-13T16:53:20Z) in Unix seconds
+	ksuidEpoch = 1400000000
+)
+
+var (
+	// gregorianEpoch is the start of the UUID v1/v6 timestamp (1582-10-15T00:00:00Z)
+	gregorianEpoch = time.Date(1582, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	objectIDPattern  = regexp.MustCompile(`^[0-9a-fA-F]{24}$`)
+	uuidPattern      = regexp.MustCompile(`^(?i)(urn:uuid:)?\{?([0-9a-f]{8})-?([0-9a-f]{4})-?([0-9a-f]{4})-?([0-9a-f]{4})-?([0-9a-f]{12})\}?$`)
+	ulidPattern      = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}$`)
+	ksuidPattern     = regexp.MustCompile(`^[0-9A-Za-z]{27}$`)
+	snowflakePattern = regexp.MustCompile(`^[0-9]{1,20}$`)
+)
+
+// snowflakeLayout describes how a Snowflake-style ID packs its timestamp and other fields
+type snowflakeLayout struct {
+	// epoch is the custom epoch in Unix milliseconds
+	epoch int64
+	// unit is the timestamp tick length
+	unit time.Duration
+	// timestampShift is the number of low bits below the timestamp
+	timestampShift uint
+	// fields lists the remaining fields from high to low bits
+	fields []snowflakeField
+}
+
+type snowflakeField struct {
+	name string
+	bits uint
+}
+
+var snowflakePresets = map[string]snowflakeLayout{
+	"twitter": {
+		epoch: 1288834974657, unit: time.Millisecond, timestampShift: 22,
+		fields: []snowflakeField{{"datacenter_id", 5}, {"worker_id", 5}, {"sequence", 12}},
+	},
+	"discord": {
+		epoch: 1420070400000, unit: time.Millisecond, timestampShift: 22,
+		fields: []snowflakeField{{"worker_id", 5}, {"process_id", 5}, {"increment", 12}},
+	},
+	"instagram": {
+		epoch: 1314220021721, unit: time.Millisecond, timestampShift: 23,
+		fields: []snowflakeField{{"shard_id", 13}, {"sequence", 10}},
+	},
+	"sonyflake": {
+		epoch: 1409529600000, unit: 10 * time.Millisecond, timestampShift: 24,
+		fields: []snowflakeField{{"sequence", 8}, {"machine_id", 16}},
+	},
+}
+
+// decodedID is the timestamp and fields extracted from a time-based ID
+type decodedID struct {
+	idType string
+	// timestamp is the raw embedded timestamp value, counted in unit
+	timestamp int64
+	unit      string
+	time      time.Time
+	fields    map[string]any
+}
+
+// detectIDType guesses the ID type from its shape
+func detectIDType(id string) (string, error) {
+	switch {
+	case objectIDPattern.MatchString(id):
+		return "objectid", nil
+	case uuidPattern.MatchString(id):
+		return "uuid", nil
+	case ulidPattern.MatchString(id):
+		return "ulid", nil
+	case ksuidPattern.MatchString(id):
+		return "ksuid", nil
+	case snowflakePattern.MatchString(id):
+		return "snowflake", nil
+	}
+	return "", errors.New("unable to detect ID type, expected a ULID, UUID, Snowflake, KSUID or ObjectId")
+}
+
+// decodeULID extracts the 48-bit millisecond timestamp and 80-bit randomness from a ULID
+func decodeULID(id string) (decodedID, error) {
+	if !ulidPattern.MatchString(id) {
+		return decodedID{}, errors.New("ULID must be 26 Crockford base32 characters starting with 0-7")
+	}
+
+	// 26 characters carry 130 bits; the top two are always zero
+	value := new(big.Int)
+	for _, c := range strings.ToUpper(id) {
+		value.Lsh(value, 5)
+		value.Or(value, big.NewInt(int64(strings.IndexRune(crockfordAlphabet, c))))
+	}
+	raw := value.FillBytes(make([]byte, 16))
+
+	ms := int64(raw[0])<<40 | int64(raw[1])<<32 | int64(binary.BigEndian.Uint32(raw[2:6]))
+	return decodedID{
+		idType:    "ulid",
+		timestamp: ms,
+		unit:      "milliseconds",
+		time:      time.UnixMilli(ms).UTC(),
+		fields: map[string]any{
+			"randomness": hex.EncodeToString(raw[6:]),
+			"uuid":       formatUUID(raw),
+		},
+	}, nil
+}
+
+// decodeUUID extracts the timestamp from version 1, 6 and 7 UUIDs
+func decodeUUID(id string) (decodedID, error) {
+	match := uuidPattern.FindStringSubmatch(id)
+	if match == nil {
+		return decodedID{}, errors.New("UUID must be 32 hex digits, optionally hyphenated")
+	}
+	raw, _ := hex.DecodeString(strings.Join(match[2:], ""))
+	version := raw[6] >> 4
+	fields := map[string]any{"version": version, "variant": uuidVariant(raw[8])}
+
+	switch version {
+	case 1, 6:
+		timeLow := uint64(binary.BigEndian.Uint32(raw[0:4]))
+		timeMid := uint64(binary.BigEndian.Uint16(raw[4:6]))
+		timeHigh := uint64(binary.BigEndian.Uint16(raw[6:8]) & 0x0FFF)
+		var ticks uint64
+		if version == 1 {
+			ticks = timeHigh<<48 | timeMid<<32 | timeLow
+		} else {
+			// v6 stores the same 60-bit timestamp most significant bits first
+			ticks = timeLow<<28 | timeMid<<12 | timeHigh
+		}
+		node := raw[10:16]
+		fields["clock_sequence"] = binary.BigEndian.Uint16(raw[8:10]) & 0x3FFF
+		fields["node"] = formatMAC(node)
+		// The multicast bit marks a random node id instead of a real MAC address
+		fields["node_is_random"] = node[0]&0x01 == 1
+
+		t := time.Unix(gregorianEpoch.Unix()+int64(ticks/10000000), int64(ticks%10000000)*100).UTC()
+		return decodedID{
+			idType:    "uuid",
+			timestamp: int64(ticks),
+			unit:      "100-nanosecond intervals since 1582-10-15",
+			time:      t,
+			fields:    fields,
+		}, nil
+	case 7:
+		ms := int64(binary.BigEndian.Uint16(raw[0:2]))<<32 | int64(binary.BigEndian.Uint32(raw[2:6]))
+		fields["rand_a"] = binary.BigEndian.Uint16(raw[6:8]) & 0x0FFF
+		fields["rand_b"] = hex.EncodeToString(append([]byte{raw[8] & 0x3F}, raw[9:]...))
+		return decodedID{
+			idType:    "uuid",
+			timestamp: ms,
+			unit:      "milliseconds",
+			time:      time.UnixMilli(ms).UTC(),
+			fields:    fields,
+		}, nil
+	default:
+		return decodedID{}, errors.New("UUID version " + strconv.Itoa(int(version)) + " does not embed a timestamp, only versions 1, 6 and 7 do")
+	}
+}
+
+// decodeSnowflake splits a Snowflake ID using a named preset layout or a custom epoch
+func decodeSnowflake(id, preset string, customEpoch *int64) (decodedID, error) {
+	value, err := strconv.ParseUint(id, 10, 64)
+	if err != nil || value >= 1<<63 {
+		return decodedID{}, errors.New("snowflake must be a positive 63-bit integer")
+	}
+
+	if preset == "" {
+		preset = "twitter"
+	}
+	layout, ok := snowflakePresets[preset]
+	if !ok && preset != "custom" {
+		return decodedID{}, errors.New("snowflake preset must be one of 'twitter', 'discord', 'instagram', 'sonyflake' or 'custom'")
+	}
+	if preset == "custom" {
+		if customEpoch == nil {
+			return decodedID{}, errors.New("snowflake_epoch is required for the custom preset")
+		}
+		// Custom IDs are assumed to use the common Twitter bit layout
+		layout = snowflakePresets["twitter"]
+	}
+	if customEpoch != nil {
+		layout.epoch = *customEpoch
+	}
+
+	ticks := int64(value >> layout.timestampShift)
+	fields := map[string]any{"preset": preset, "epoch": layout.epoch}
+	shift := layout.timestampShift
+	for _, field := range layout.fields {
+		shift -= field.bits
+		fields[field.name] = (value >> shift) & (1<<field.bits - 1)
+	}
+
+	t := time.UnixMilli(layout.epoch).Add(time.Duration(ticks) * layout.unit).UTC()
+	unit := "milliseconds since custom epoch"
+	if layout.unit != time.Millisecond {
+		unit = layout.unit.String() + " ticks since custom epoch"
+	}
+	return decodedID{
+		idType:    "snowflake",
+		timestamp: ticks,
+		unit:      unit,
+		time:      t,
+		fields:    fields,
+	}, nil
+}
+
+// decodeKSUID extracts the 32-bit second timestamp and 128-bit payload from a KSUID
+func decodeKSUID(id string) (decodedID, error) {
+	if !ksuidPattern.MatchString(id) {
+		return decodedID{}, errors.New("KSUID must be 27 base62 characters")
+	}
+
+	value := new(big.Int)
+	base := big.NewInt(62)
+	for _, c := range id {
+		value.Mul(value, base)
+		value.Add(value, big.NewInt(int64(strings.IndexRune(base62Alphabet, c))))
+	}
+	if value.BitLen() > 160 {
+		return decodedID{}, errors.New("KSUID value exceeds 160 bits")
+	}
+	raw := value.FillBytes(make([]byte, 20))
+
+	seconds := int64(binary.BigEndian.Uint32(raw[0:4]))
+	return decodedID{
+		idType:    "ksuid",
+		timestamp: seconds,
+		unit:      "seconds since 2014-05-13T16:53:20Z",
+		time:      time.Unix(seconds+ksuidEpoch, 0).UTC(),
+		fields:    map[string]any{"payload": hex.EncodeToString(raw[4:])},
+	}, nil
+}
+
+// decodeObjectID extracts the 32-bit second timestamp, random value and counter from a MongoDB ObjectId
+func decodeObjectID(id string) (decodedID, error) {
+	raw, err := hex.DecodeString(id)
+	if err != nil || len(raw) != 12 {
+		return decodedID{}, errors.New("ObjectId must be 24 hex digits")
+	}
+
+	seconds := int64(binary.BigEndian.Uint32(raw[0:4]))
+	return decodedID{
+		idType:    "objectid",
+		timestamp: seconds,
+		unit:      "seconds",
+		time:      time.Unix(seconds, 0).UTC(),
+		fields: map[string]any{
+			"random":  hex.EncodeToString(raw[4:9]),
+			"counter": uint32(raw[9])<<16 | uint32(raw[10])<<8 | uint32(raw[11]),
+		},
+	}, nil
+}
+
+// uuidVariant names the UUID variant from the high bits of byte 8
+func uuidVariant(b byte) string {
+	switch {
+	case b&0x80 == 0:
+		return "ncs"
+	case b&0xC0 == 0x80:
+		return "rfc4122"
+	case b&0xE0 == 0xC0:
+		return "microsoft"
+	default:
+		return "future"
+	}
+}
+
+// formatUUID prints 16 bytes in the canonical hyphenated UUID form
+func formatUUID(raw []byte) string {
+	h := hex.EncodeToString(raw)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+// formatMAC prints bytes as a colon-separated MAC address
+func formatMAC(raw []byte) string {
+	parts := make([]string, len(raw))
+	for i, b := range raw {
+		parts[i] = hex.EncodeToString([]byte{b})
+	}
+	return strings.Join(parts, ":")
+}
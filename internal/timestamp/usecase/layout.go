@@ -0,0 +1,322 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// namedLayouts maps preset output format names to Go layouts
+var namedLayouts = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"rfc1123":     time.RFC1123,
+	"rfc1123z":    time.RFC1123Z,
+	"rfc822":      time.RFC822,
+	"rfc822z":     time.RFC822Z,
+	"rfc850":      time.RFC850,
+	"ansic":       time.ANSIC,
+	"unixdate":    time.UnixDate,
+	"rubydate":    time.RubyDate,
+	"kitchen":     time.Kitchen,
+	"stamp":       time.Stamp,
+	"stampmilli":  time.StampMilli,
+	"stampmicro":  time.StampMicro,
+	"stampnano":   time.StampNano,
+	"datetime":    time.DateTime,
+	"dateonly":    time.DateOnly,
+	"timeonly":    time.TimeOnly,
+	"iso8601":     "2006-01-02T15:04:05.000Z07:00",
+	"sql":         "2006-01-02 15:04:05.999999",
+}
+
+// literalProbe is formatted with candidate literal text to check it holds no Go layout tokens;
+// every field differs from the reference time so any token changes the output
+var literalProbe = time.Date(2009, 11, 17, 8, 34, 58, 651387237, time.FixedZone("XXX", 5*3600+30*60))
+
+// strftimeDirectives maps strftime conversion characters to Go layout fragments
+var strftimeDirectives = map[string]string{
+	"Y": "2006", "y": "06",
+	"m": "01", "-m": "1", "b": "Jan", "h": "Jan", "B": "January",
+	"d": "02", "-d": "2", "e": "_2", "j": "002",
+	"a": "Mon", "A": "Monday",
+	"H": "15", "I": "03", "-I": "3", "M": "04", "-M": "4", "S": "05", "-S": "5",
+	"p": "PM", "P": "pm",
+	"Z": "MST", "z": "-0700", ":z": "-07:00",
+	"F": "2006-01-02", "T": "15:04:05", "D": "01/02/06", "R": "15:04", "r": "03:04:05 PM",
+	"c": "Mon Jan _2 15:04:05 2006", "x": "01/02/06", "X": "15:04:05",
+	"%": "%", "n": "\n", "t": "\t",
+}
+
+// strftimeFractions maps fractional second directives to their digit count; Python's %f, Ruby's %L and %N
+var strftimeFractions = map[string]int{"f": 6, "L": 3, "N": 9}
+
+// layoutSyntaxes lists the accepted layout syntax names
+var layoutSyntaxes = []string{"go", "strftime", "java", "icu", "moment"}
+
+// toGoLayout converts a layout in the given syntax to a Go reference-time layout
+func toGoLayout(layout, syntax string) (string, error) {
+	switch strings.ToLower(syntax) {
+	case "", "go":
+		return layout, nil
+	case "strftime":
+		return strftimeToGo(layout)
+	case "java", "icu":
+		return patternToGo(layout, javaTokens, '\'')
+	case "moment":
+		return patternToGo(layout, momentTokens, '[')
+	}
+	return "", errors.New("unsupported layout syntax: " + syntax + ", expected one of " + strings.Join(layoutSyntaxes, ", "))
+}
+
+// goLayoutBuilder assembles a Go layout, refusing literal text that Go would read as a token
+type goLayoutBuilder struct {
+	strings.Builder
+}
+
+func (b *goLayoutBuilder) literal(text string) error {
+	if text == "" {
+		return nil
+	}
+	if literalProbe.Format(text) != text {
+		return fmt.Errorf("literal text %q cannot be expressed in a Go layout", text)
+	}
+	b.WriteString(text)
+	return nil
+}
+
+// fraction appends n fractional second digits; Go requires them right after a '.' or ',' separator
+func (b *goLayoutBuilder) fraction(n int) error {
+	current := b.String()
+	if current == "" || (current[len(current)-1] != '.' && current[len(current)-1] != ',') {
+		return errors.New("fractional seconds must follow a '.' or ',' separator")
+	}
+	b.WriteString(strings.Repeat("0", n))
+	return nil
+}
+
+// strftimeToGo converts a strftime/strptime format to a Go layout
+func strftimeToGo(layout string) (string, error) {
+	var b goLayoutBuilder
+	var text strings.Builder
+	for i := 0; i < len(layout); i++ {
+		if layout[i] != '%' {
+			text.WriteByte(layout[i])
+			continue
+		}
+		if i+1 >= len(layout) {
+			return "", errors.New("dangling '%' at end of layout")
+		}
+
+		// Read an optional '-' (no padding) or ':' (colon offset) flag before the directive
+		directive := layout[i+1 : i+2]
+		if (directive == "-" || directive == ":") && i+2 < len(layout) {
+			directive = layout[i+1 : i+3]
+		}
+		i += len(directive)
+
+		if err := b.literal(text.String()); err != nil {
+			return "", err
+		}
+		text.Reset()
+
+		if digits, ok := strftimeFractions[directive]; ok {
+			if err := b.fraction(digits); err != nil {
+				return "", err
+			}
+			continue
+		}
+		fragment, ok := strftimeDirectives[directive]
+		if !ok {
+			return "", errors.New("unsupported strftime directive: %" + directive)
+		}
+		if directive == "%" || directive == "n" || directive == "t" {
+			// These are literal characters, subject to the same token check as other text
+			text.WriteString(fragment)
+			continue
+		}
+		b.WriteString(fragment)
+	}
+	if err := b.literal(text.String()); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// patternToken maps a run of one pattern letter to a Go layout fragment by run length
+// A fraction token produces that many fractional digits instead
+type patternToken struct {
+	fragments map[int]string
+	// longest is used for runs longer than any listed length
+	longest  string
+	fraction bool
+}
+
+// javaTokens maps Java DateTimeFormatter / ICU SimpleDateFormat letters to Go layout fragments
+var javaTokens = map[byte]patternToken{
+	'y': {fragments: map[int]string{1: "2006", 2: "06", 3: "2006"}, longest: "2006"},
+	'u': {fragments: map[int]string{1: "2006", 2: "06", 3: "2006"}, longest: "2006"},
+	'M': {fragments: map[int]string{1: "1", 2: "01", 3: "Jan"}, longest: "January"},
+	'L': {fragments: map[int]string{1: "1", 2: "01", 3: "Jan"}, longest: "January"},
+	'd': {fragments: map[int]string{1: "2", 2: "02"}},
+	'D': {fragments: map[int]string{3: "002"}},
+	'E': {fragments: map[int]string{1: "Mon", 2: "Mon", 3: "Mon"}, longest: "Monday"},
+	'a': {fragments: map[int]string{1: "PM"}},
+	'H': {fragments: map[int]string{1: "15", 2: "15"}},
+	'h': {fragments: map[int]string{1: "3", 2: "03"}},
+	'm': {fragments: map[int]string{1: "4", 2: "04"}},
+	's': {fragments: map[int]string{1: "5", 2: "05"}},
+	'S': {fraction: true},
+	'z': {fragments: map[int]string{1: "MST", 2: "MST", 3: "MST"}},
+	'Z': {fragments: map[int]string{1: "-0700", 2: "-0700", 3: "-0700", 5: "Z07:00"}},
+	'X': {fragments: map[int]string{1: "Z07", 2: "Z0700", 3: "Z07:00"}},
+	'x': {fragments: map[int]string{1: "-07", 2: "-0700", 3: "-07:00"}},
+}
+
+// momentTokens maps moment.js / Day.js format letters to Go layout fragments
+var momentTokens = map[byte]patternToken{
+	'Y': {fragments: map[int]string{2: "06", 4: "2006"}},
+	'M': {fragments: map[int]string{1: "1", 2: "01", 3: "Jan", 4: "January"}},
+	'D': {fragments: map[int]string{1: "2", 2: "02", 4: "002"}},
+	'd': {fragments: map[int]string{3: "Mon", 4: "Monday"}},
+	'H': {fragments: map[int]string{1: "15", 2: "15"}},
+	'h': {fragments: map[int]string{1: "3", 2: "03"}},
+	'm': {fragments: map[int]string{1: "4", 2: "04"}},
+	's': {fragments: map[int]string{1: "5", 2: "05"}},
+	'S': {fraction: true},
+	'A': {fragments: map[int]string{1: "PM"}},
+	'a': {fragments: map[int]string{1: "pm"}},
+	'Z': {fragments: map[int]string{1: "-07:00", 2: "-0700"}},
+	'z': {fragments: map[int]string{1: "MST", 2: "MST"}},
+}
+
+// patternToGo converts a letter-run pattern (Java/ICU or moment.js) to a Go layout
+// Quoted literals use quote ('...' in Java, where a doubled quote is a quote, [...] in moment); other non-letters are literal
+func patternToGo(layout string, tokens map[byte]patternToken, quote byte) (string, error) {
+	var b goLayoutBuilder
+	var text strings.Builder
+	for i := 0; i < len(layout); {
+		c := layout[i]
+
+		// Quoted literal text; in Java a doubled quote stands for a quote, inside or outside quotes
+		if c == quote {
+			closing := byte('\'')
+			if quote == '[' {
+				closing = ']'
+			}
+			j := i + 1
+			for {
+				if j >= len(layout) {
+					return "", fmt.Errorf("unterminated quoted literal at position %d", i)
+				}
+				if layout[j] == closing {
+					if quote == '\'' && j+1 < len(layout) && layout[j+1] == '\'' {
+						text.WriteByte('\'')
+						j += 2
+						continue
+					}
+					break
+				}
+				text.WriteByte(layout[j])
+				j++
+			}
+			if quote == '\'' && j == i+1 {
+				text.WriteByte('\'')
+			}
+			i = j + 1
+			continue
+		}
+
+		isLetter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if !isLetter {
+			text.WriteByte(c)
+			i++
+			continue
+		}
+
+		// Measure the run of the same letter
+		run := 1
+		for i+run < len(layout) && layout[i+run] == c {
+			run++
+		}
+		pattern := layout[i : i+run]
+		i += run
+
+		if err := b.literal(text.String()); err != nil {
+			return "", err
+		}
+		text.Reset()
+
+		token, ok := tokens[c]
+		if !ok {
+			return "", errors.New("unsupported pattern token: " + pattern)
+		}
+		if token.fraction {
+			if err := b.fraction(min(run, 9)); err != nil {
+				return "", err
+			}
+			continue
+		}
+		fragment, ok := token.fragments[run]
+		if !ok && token.longest != "" && run > len(token.fragments) {
+			fragment, ok = token.longest, true
+		}
+		if !ok {
+			return "", errors.New("unsupported pattern token: " + pattern)
+		}
+		b.WriteString(fragment)
+	}
+	if err := b.literal(text.String()); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// parseWithLayout parses a date string with a caller-supplied layout
+// Like tryParseDateFormats, zone-less input is returned as a wall clock in UTC
+func parseWithLayout(dateString, layout, syntax string, loc *time.Location) (time.Time, dateFormat, error) {
+	goLayout, err := toGoLayout(layout, syntax)
+	if err != nil {
+		return time.Time{}, dateFormat{}, err
+	}
+
+	wall, err := time.Parse(goLayout, dateString)
+	if err != nil {
+		return time.Time{}, dateFormat{}, errors.New("date string does not match layout: " + err.Error())
+	}
+
+	// The input carries its own zone if parsing it in another location gives the same instant
+	format := dateFormat{layout: goLayout, name: "custom"}
+	probe, _ := time.ParseInLocation(goLayout, dateString, time.FixedZone("", 3600))
+	if !probe.Equal(wall) {
+		return wall, format, nil
+	}
+
+	format.zoned = true
+	t, err := time.ParseInLocation(goLayout, dateString, loc)
+	return t, format, err
+}
+
+// formatOutputs renders t with each requested output format, keyed by the format as given
+// Formats are preset names (e.g., "rfc1123", "kitchen") or layouts in the given syntax; month and weekday names
+// in layouts are rendered in lang, while presets are standard formats and always keep English names
+func formatOutputs(t time.Time, formats []string, syntax string, lang *language) (map[string]string, error) {
+	if len(formats) == 0 {
+		return nil, nil
+	}
+
+	formatted := make(map[string]string, len(formats))
+	for _, format := range formats {
+		if goLayout, ok := namedLayouts[strings.ToLower(format)]; ok {
+			formatted[format] = t.Format(goLayout)
+			continue
+		}
+		goLayout, err := toGoLayout(format, syntax)
+		if err != nil {
+			return nil, fmt.Errorf("invalid output format %q: %w", format, err)
+		}
+		formatted[format] = lang.format(t, goLayout)
+	}
+	return formatted, nil
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// language holds the words used for localized relative times and month/weekday names
+type language struct {
+	months, shortMonths     [12]string
+	weekdays, shortWeekdays [7]string // Sunday first, like time.Weekday
+	// units are the singular and plural words for years, months, days, hours, minutes and seconds
+	units [6][2]string
+	// spaced puts a space between a number and its unit
+	spaced bool
+	// separator joins the parts of a precise relative time
+	separator string
+	// past and future wrap a relative time, with %s standing for the amount
+	past, future, now string
+}
+
+// languages are the supported output languages, keyed by language subtag
+var languages = map[string]*language{
+	"en": {
+		months:        [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		shortWeekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		units:         [6][2]string{{"year", "years"}, {"month", "months"}, {"day", "days"}, {"hour", "hours"}, {"minute", "minutes"}, {"second", "seconds"}},
+		spaced:        true,
+		separator:     ", ",
+		past:          "%s ago",
+		future:        "in %s",
+		now:           "now",
+	},
+	"vi": {
+		months:        [12]string{"tháng 1", "tháng 2", "tháng 3", "tháng 4", "tháng 5", "tháng 6", "tháng 7", "tháng 8", "tháng 9", "tháng 10", "tháng 11", "tháng 12"},
+		shortMonths:   [12]string{"thg 1", "thg 2", "thg 3", "thg 4", "thg 5", "thg 6", "thg 7", "thg 8", "thg 9", "thg 10", "thg 11", "thg 12"},
+		weekdays:      [7]string{"Chủ Nhật", "Thứ Hai", "Thứ Ba", "Thứ Tư", "Thứ Năm", "Thứ Sáu", "Thứ Bảy"},
+		shortWeekdays: [7]string{"CN", "T2", "T3", "T4", "T5", "T6", "T7"},
+		units:         [6][2]string{{"năm", "năm"}, {"tháng", "tháng"}, {"ngày", "ngày"}, {"giờ", "giờ"}, {"phút", "phút"}, {"giây", "giây"}},
+		spaced:        true,
+		separator:     ", ",
+		past:          "%s trước",
+		future:        "%s tới",
+		now:           "bây giờ",
+	},
+	"ja": {
+		months:        [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		shortMonths:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		weekdays:      [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		shortWeekdays: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		units:         [6][2]string{{"年", "年"}, {"か月", "か月"}, {"日", "日"}, {"時間", "時間"}, {"分", "分"}, {"秒", "秒"}},
+		spaced:        false,
+		separator:     "",
+		past:          "%s前",
+		future:        "%s後",
+		now:           "今",
+	},
+	"fr": {
+		months:        [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		weekdays:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortWeekdays: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		units:         [6][2]string{{"an", "ans"}, {"mois", "mois"}, {"jour", "jours"}, {"heure", "heures"}, {"minute", "minutes"}, {"seconde", "secondes"}},
+		spaced:        true,
+		separator:     ", ",
+		past:          "il y a %s",
+		future:        "dans %s",
+		now:           "maintenant",
+	},
+	"de": {
+		months:        [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths:   [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortWeekdays: [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		// "vor" and "in" both take the dative
+		units:     [6][2]string{{"Jahr", "Jahren"}, {"Monat", "Monaten"}, {"Tag", "Tagen"}, {"Stunde", "Stunden"}, {"Minute", "Minuten"}, {"Sekunde", "Sekunden"}},
+		spaced:    true,
+		separator: ", ",
+		past:      "vor %s",
+		future:    "in %s",
+		now:       "jetzt",
+	},
+	"es": {
+		months:        [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		weekdays:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortWeekdays: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		units:         [6][2]string{{"año", "años"}, {"mes", "meses"}, {"día", "días"}, {"hora", "horas"}, {"minuto", "minutos"}, {"segundo", "segundos"}},
+		spaced:        true,
+		separator:     ", ",
+		past:          "hace %s",
+		future:        "dentro de %s",
+		now:           "ahora",
+	},
+}
+
+// resolveLanguage returns the output language for a locale such as "fr", "de-AT" or "ja_JP"
+// Unsupported and empty locales fall back to English
+func resolveLanguage(locale string) *language {
+	tag := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	subtag, _, _ := strings.Cut(tag, "-")
+	if lang, ok := languages[subtag]; ok {
+		return lang
+	}
+	return languages["en"]
+}
+
+// relativeParts renders the non-zero parts of a calendar duration, largest first, down to seconds
+func (lang *language) relativeParts(d calendarDuration) []string {
+	c := durationComponents(d)
+	parts := []string{}
+	for i, value := range []int64{int64(c.Years), int64(c.Months), int64(c.Days), c.Hours, c.Minutes, c.Seconds} {
+		if value == 0 {
+			continue
+		}
+		unit := lang.units[i][1]
+		if value == 1 {
+			unit = lang.units[i][0]
+		}
+		if lang.spaced {
+			unit = " " + unit
+		}
+		parts = append(parts, strconv.FormatInt(value, 10)+unit)
+	}
+	return parts
+}
+
+// relativeTime describes t relative to reference, both precisely ("1 year, 3 months, 4 days ago") and by its
+// largest unit only ("1 year ago"); years, months and days are counted on the calendar in loc
+func (lang *language) relativeTime(t, reference time.Time, loc *time.Location) (precise, coarse string) {
+	template := lang.future
+	start, end := reference, t
+	if t.Before(reference) {
+		template, start, end = lang.past, t, reference
+	}
+
+	parts := lang.relativeParts(calendarDiff(start, end, loc))
+	if len(parts) == 0 {
+		return lang.now, lang.now
+	}
+	return strings.Replace(template, "%s", strings.Join(parts, lang.separator), 1), strings.Replace(template, "%s", parts[0], 1)
+}
+
+// format renders t with a Go layout, replacing month and weekday names with the language's
+// Names are found the way time.Format finds them, so "Jan" and "Mon" are not names when a lowercase letter follows
+func (lang *language) format(t time.Time, layout string) string {
+	if lang == languages["en"] {
+		return t.Format(layout)
+	}
+
+	var b strings.Builder
+	start := 0
+	for i := 0; i < len(layout); i++ {
+		var name string
+		var width int
+		switch {
+		case strings.HasPrefix(layout[i:], "January"):
+			name, width = lang.months[t.Month()-1], 7
+		case strings.HasPrefix(layout[i:], "Jan") && !startsWithLower(layout[i+3:]):
+			name, width = lang.shortMonths[t.Month()-1], 3
+		case strings.HasPrefix(layout[i:], "Monday"):
+			name, width = lang.weekdays[t.Weekday()], 6
+		case strings.HasPrefix(layout[i:], "Mon") && !startsWithLower(layout[i+3:]):
+			name, width = lang.shortWeekdays[t.Weekday()], 3
+		default:
+			continue
+		}
+		b.WriteString(t.Format(layout[start:i]))
+		b.WriteString(name)
+		i += width - 1
+		start = i + 1
+	}
+	b.WriteString(t.Format(layout[start:]))
+	return b.String()
+}
+
+// startsWithLower reports whether s begins with an ASCII lowercase letter
+func startsWithLower(s string) bool {
+	return s != "" && s[0] >= 'a' && s[0] <= 'z'
+}
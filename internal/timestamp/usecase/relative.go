@@ -0,0 +1,398 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// calendarDuration is a duration whose calendar parts are kept apart from elapsed time,
+// so months follow month lengths and days keep the wall clock across DST changes
+type calendarDuration struct {
+	months int
+	days   int
+	clock  time.Duration
+}
+
+func (d calendarDuration) negate() calendarDuration {
+	return calendarDuration{months: -d.months, days: -d.days, clock: -d.clock}
+}
+
+// durationUnit is how much one of a unit adds to a calendarDuration
+type durationUnit struct {
+	months int
+	days   int
+	clock  time.Duration
+}
+
+// durationUnits maps unit names and abbreviations to their size; "m" is minutes as in Go durations
+var durationUnits = map[string]durationUnit{}
+
+func init() {
+	for names, unit := range map[string]durationUnit{
+		"ns nsec nsecs nanosecond nanoseconds":         {clock: time.Nanosecond},
+		"us µs μs usec usecs microsecond microseconds": {clock: time.Microsecond},
+		"ms msec msecs millisecond milliseconds":       {clock: time.Millisecond},
+		"s sec secs second seconds":                    {clock: time.Second},
+		"m min mins minute minutes":                    {clock: time.Minute},
+		"h hr hrs hour hours":                          {clock: time.Hour},
+		"d day days":                                   {days: 1},
+		"w wk wks week weeks":                          {days: 7},
+		"mo mon mons month months":                     {months: 1},
+		"q quarter quarters":                           {months: 3},
+		"y yr yrs year years":                          {months: 12},
+		"decade decades":                               {months: 120},
+	} {
+		for _, name := range strings.Fields(names) {
+			durationUnits[name] = unit
+		}
+	}
+}
+
+var (
+	// durationTermPattern matches one "<number> <unit>" term; "a"/"an" count as one
+	durationTermPattern = regexp.MustCompile(`([+-]?\d+(?:\.\d+)?|\ban?\b)\s*([a-zµμ]+)`)
+	// durationSeparatorPattern matches what may appear between terms
+	durationSeparatorPattern = regexp.MustCompile(`^[\s,]*(?:and)?[\s,]*$`)
+)
+
+// parseHumanDuration parses durations such as "2 days 3 hours", "an hour and 30 minutes" or "2h30m"
+// Clock units may be fractional; calendar units (days and longer) must be whole numbers
+func parseHumanDuration(input string) (calendarDuration, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	matches := durationTermPattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return calendarDuration{}, errors.New("no duration found in " + strconv.Quote(input))
+	}
+
+	var d calendarDuration
+	var clock float64
+	previous := 0
+	for _, m := range matches {
+		if !durationSeparatorPattern.MatchString(s[previous:m[0]]) {
+			return calendarDuration{}, errors.New("unexpected text " + strconv.Quote(strings.TrimSpace(s[previous:m[0]])) + " in duration")
+		}
+		previous = m[1]
+
+		number, unitName := s[m[2]:m[3]], s[m[4]:m[5]]
+		unit, ok := durationUnits[unitName]
+		if !ok {
+			return calendarDuration{}, errors.New("unknown duration unit: " + unitName)
+		}
+		value := 1.0
+		if number != "a" && number != "an" {
+			value, _ = strconv.ParseFloat(number, 64)
+		}
+
+		if unit.clock != 0 {
+			clock += value * float64(unit.clock)
+			continue
+		}
+		if value != math.Trunc(value) {
+			return calendarDuration{}, fmt.Errorf("fractional %s are not supported", unitName)
+		}
+		if math.Abs(value) > math.MaxInt32 {
+			return calendarDuration{}, errors.New("duration is out of range")
+		}
+		d.months += int(value) * unit.months
+		d.days += int(value) * unit.days
+	}
+	if !durationSeparatorPattern.MatchString(s[previous:]) {
+		return calendarDuration{}, errors.New("unexpected text " + strconv.Quote(strings.TrimSpace(s[previous:])) + " in duration")
+	}
+
+	if math.Abs(clock) >= math.MaxInt64 {
+		return calendarDuration{}, errors.New("duration is out of range")
+	}
+	d.clock = time.Duration(math.Round(clock))
+	return d, nil
+}
+
+// addCalendar adds d to t in loc: months keep the day of month, clamped to the target month's length,
+// days keep the wall clock time, and the clock part is added as exact elapsed time
+func addCalendar(t time.Time, d calendarDuration, loc *time.Location) time.Time {
+	if d.months == 0 && d.days == 0 {
+		return t.Add(d.clock)
+	}
+
+	local := t.In(loc)
+	year, month, day := local.Date()
+	months := int(month) - 1 + d.months
+	year += months / 12
+	if months %= 12; months < 0 {
+		months += 12
+		year--
+	}
+	month = time.Month(months + 1)
+	day = min(day, daysInMonth(year, month))
+
+	wall := time.Date(year, month, day, local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC).AddDate(0, 0, d.days)
+	result, _, _ := LocalizeWallClock(wall, loc)
+	return result.Add(d.clock)
+}
+
+// daysInMonth returns the number of days in the month
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// wallDate returns midnight of t's date in loc as a wall clock held in UTC
+func wallDate(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+var (
+	isoWeekDatePattern    = regexp.MustCompile(`^(\d{4})-?w(\d{2})(?:-?([1-7]))?$`)
+	isoOrdinalDatePattern = regexp.MustCompile(`^(\d{4})-?(\d{3})$`)
+	timeOfDayPattern      = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(?::(\d{2}))?\s*(am|pm)?$`)
+	periodBoundaryPattern = regexp.MustCompile(`^(start|beginning|end) of (?:the )?(?:(this|next|last|previous) )?(day|week|month|quarter|year)$`)
+)
+
+// weekdays maps weekday names and abbreviations to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// parseNaturalDate parses ISO 8601 week and ordinal dates and relative expressions such as "now",
+// "yesterday 14:00", "next friday", "3 days ago", "in 2h30m" or "end of month", relative to ref in loc
+// Week and ordinal dates are returned as wall clocks in UTC like tryParseDateFormats; relative expressions as instants
+func parseNaturalDate(input string, ref time.Time, loc *time.Location) (parsedDate, error) {
+	s := strings.Join(strings.Fields(strings.ToLower(input)), " ")
+	relative := dateFormat{name: "relative", zoned: true}
+
+	if m := isoWeekDatePattern.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		weekday := 1
+		if m[3] != "" {
+			weekday, _ = strconv.Atoi(m[3])
+		}
+		if _, lastWeek := time.Date(year, 12, 28, 0, 0, 0, 0, time.UTC).ISOWeek(); week < 1 || week > lastWeek {
+			return parsedDate{}, fmt.Errorf("week %d does not exist in %d", week, year)
+		}
+		// January 4th is always in week 1
+		jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.UTC)
+		monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+		return parsedDate{time: monday.AddDate(0, 0, (week-1)*7+weekday-1), format: dateFormat{name: "ISO8601_week_date"}}, nil
+	}
+
+	if m := isoOrdinalDatePattern.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		ordinal, _ := strconv.Atoi(m[2])
+		if days := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay(); ordinal < 1 || ordinal > days {
+			return parsedDate{}, fmt.Errorf("day %d does not exist in %d", ordinal, year)
+		}
+		return parsedDate{time: time.Date(year, 1, ordinal, 0, 0, 0, 0, time.UTC), format: dateFormat{name: "ISO8601_ordinal_date"}}, nil
+	}
+
+	if s == "now" {
+		return parsedDate{time: ref, format: relative}, nil
+	}
+
+	// Offsets from the reference time: "3 days ago", "in 2h30m", "+90m", "-1 week"
+	if rest, ok := strings.CutSuffix(s, " ago"); ok {
+		d, err := parseHumanDuration(rest)
+		if err != nil {
+			return parsedDate{}, err
+		}
+		return parsedDate{time: addCalendar(ref, d.negate(), loc), format: relative}, nil
+	}
+	if rest, ok := strings.CutPrefix(s, "in "); ok {
+		d, err := parseHumanDuration(rest)
+		if err != nil {
+			return parsedDate{}, err
+		}
+		return parsedDate{time: addCalendar(ref, d, loc), format: relative}, nil
+	}
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		d, err := parseHumanDuration(s[1:])
+		if err != nil {
+			return parsedDate{}, err
+		}
+		if s[0] == '-' {
+			d = d.negate()
+		}
+		return parsedDate{time: addCalendar(ref, d, loc), format: relative}, nil
+	}
+
+	// Period boundaries: "end of month", "start of next week"
+	if m := periodBoundaryPattern.FindStringSubmatch(s); m != nil {
+		return parsedDate{time: periodBoundary(ref, loc, m[1] != "end", m[2], m[3]), format: relative}, nil
+	}
+
+	// A day expression optionally followed by a time of day: "yesterday 14:00", "next friday at 9am", "noon"
+	words := strings.Fields(s)
+	for split := len(words); split >= 0; split-- {
+		dayWords, timeWords := words[:split], words[split:]
+		if len(timeWords) > 0 && timeWords[0] == "at" {
+			timeWords = timeWords[1:]
+		}
+
+		var clock time.Duration
+		hasClock := len(timeWords) > 0
+		if hasClock {
+			var ok bool
+			if clock, ok = parseTimeOfDay(strings.Join(timeWords, " ")); !ok {
+				continue
+			}
+		} else if split < len(words) {
+			// A dangling "at"
+			continue
+		}
+
+		wall, keepsClock, ok := parseDayExpression(strings.Join(dayWords, " "), ref, loc, hasClock)
+		if !ok {
+			continue
+		}
+		if hasClock {
+			wall = time.Date(wall.Year(), wall.Month(), wall.Day(), 0, 0, 0, 0, time.UTC).Add(clock)
+		} else if !keepsClock {
+			wall = time.Date(wall.Year(), wall.Month(), wall.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		t, _, _ := LocalizeWallClock(wall, loc)
+		return parsedDate{time: t, format: relative}, nil
+	}
+
+	return parsedDate{}, errors.New("unable to parse date string with any supported format or relative expression")
+}
+
+// parseTimeOfDay parses "14:00", "14:00:30", "2pm", "2:30 pm", "noon" and "midnight"
+// A bare number is rejected so "friday 14" is not mistaken for a time
+func parseTimeOfDay(s string) (time.Duration, bool) {
+	switch s {
+	case "noon", "midday":
+		return 12 * time.Hour, true
+	case "midnight":
+		return 0, true
+	}
+
+	m := timeOfDayPattern.FindStringSubmatch(s)
+	if m == nil || (m[2] == "" && m[4] == "") {
+		return 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	second, _ := strconv.Atoi(m[3])
+	if m[4] != "" {
+		if hour < 1 || hour > 12 {
+			return 0, false
+		}
+		hour %= 12
+		if m[4] == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 || second > 59 {
+		return 0, false
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second, true
+}
+
+// parseDayExpression resolves a day expression to a wall clock held in UTC
+// keepsClock reports that the expression also carries the reference time of day ("next week")
+// An empty expression means today, but only when a time of day follows it
+func parseDayExpression(s string, ref time.Time, loc *time.Location, hasClock bool) (wall time.Time, keepsClock bool, ok bool) {
+	today := wallDate(ref, loc)
+	switch s {
+	case "":
+		return today, false, hasClock
+	case "today", "tonight":
+		return today, false, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), false, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), false, true
+	case "day after tomorrow", "the day after tomorrow":
+		return today.AddDate(0, 0, 2), false, true
+	case "day before yesterday", "the day before yesterday":
+		return today.AddDate(0, 0, -2), false, true
+	}
+
+	modifier, name, found := strings.Cut(s, " ")
+	if !found {
+		modifier, name = "", s
+	}
+
+	// "next week", "last month": the reference time moved by one unit
+	if unit, ok := map[string]durationUnit{"week": {days: 7}, "month": {months: 1}, "year": {months: 12}}[name]; ok {
+		d := calendarDuration{months: unit.months, days: unit.days}
+		switch modifier {
+		case "next":
+		case "last", "previous":
+			d = d.negate()
+		default:
+			return time.Time{}, false, false
+		}
+		local := addCalendar(ref, d, loc).In(loc)
+		return time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC), true, true
+	}
+
+	weekday, ok := weekdays[name]
+	if !ok {
+		return time.Time{}, false, false
+	}
+	ahead := (int(weekday) - int(today.Weekday()) + 7) % 7
+	switch modifier {
+	case "":
+		// The coming occurrence, today included
+	case "next":
+		// Strictly after today
+		if ahead == 0 {
+			ahead = 7
+		}
+	case "last", "previous":
+		// Strictly before today
+		ahead -= 7
+	case "this":
+		// Within the current Monday-based week
+		ahead = (int(weekday)+6)%7 - (int(today.Weekday())+6)%7
+	default:
+		return time.Time{}, false, false
+	}
+	return today.AddDate(0, 0, ahead), false, true
+}
+
+// periodBoundary returns the first instant (start) or last nanosecond (end) of the day, Monday-based week,
+// month, quarter or year containing ref in loc, shifted one period by "next" or "last"
+func periodBoundary(ref time.Time, loc *time.Location, start bool, modifier, period string) time.Time {
+	today := wallDate(ref, loc)
+	var first time.Time
+	var length calendarDuration
+	switch period {
+	case "day":
+		first, length = today, calendarDuration{days: 1}
+	case "week":
+		first, length = today.AddDate(0, 0, -((int(today.Weekday())+6)%7)), calendarDuration{days: 7}
+	case "month":
+		first, length = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC), calendarDuration{months: 1}
+	case "quarter":
+		first, length = time.Date(today.Year(), (today.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC), calendarDuration{months: 3}
+	case "year":
+		first, length = time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC), calendarDuration{months: 12}
+	}
+
+	switch modifier {
+	case "next":
+		first = first.AddDate(0, length.months, length.days)
+	case "last", "previous":
+		first = first.AddDate(0, -length.months, -length.days)
+	}
+
+	if start {
+		t, _, _ := LocalizeWallClock(first, loc)
+		return t
+	}
+	next, _, _ := LocalizeWallClock(first.AddDate(0, length.months, length.days), loc)
+	return next.Add(-time.Nanosecond)
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	timestampmodels "konverter/internal/timestamp/models"
+)
+
+// transitionScanStep is how far apart offsets are sampled when looking for transitions;
+// no zone changes its offset twice within it
+const transitionScanStep = 12 * time.Hour
+
+// zoneTransitions returns every change of UTC offset in loc after start and up to end
+func zoneTransitions(start, end time.Time, loc *time.Location) []time.Time {
+	transitions := []time.Time{}
+	previous := start
+	_, offset := start.In(loc).Zone()
+	for previous.Before(end) {
+		next := previous.Add(transitionScanStep)
+		if next.After(end) {
+			next = end
+		}
+		if _, nextOffset := next.In(loc).Zone(); nextOffset != offset {
+			transitions = append(transitions, FindZoneTransition(previous, next, loc))
+			offset = nextOffset
+		}
+		previous = next
+	}
+	return transitions
+}
+
+// describeTransition explains how the wall clock changes at a transition
+func describeTransition(at time.Time, loc *time.Location) string {
+	before := at.Add(-time.Second).In(loc)
+	after := at.In(loc)
+	_, beforeOffset := before.Zone()
+	_, afterOffset := after.Zone()
+
+	shift := time.Duration(afterOffset-beforeOffset) * time.Second
+	direction := "forward"
+	if shift < 0 {
+		direction, shift = "back", -shift
+	}
+	wallBefore := at.Add(time.Duration(beforeOffset) * time.Second).UTC()
+	return "Clocks go " + direction + " " + formatShift(shift) + " from " + wallBefore.Format("15:04") + " to " + after.Format("15:04")
+}
+
+// formatShift renders an offset change compactly, e.g. "1h", "30m" or "1h30m"
+func formatShift(d time.Duration) string {
+	s := ""
+	if hours := d / time.Hour; hours > 0 {
+		s = strconv.Itoa(int(hours)) + "h"
+	}
+	if minutes := d % time.Hour / time.Minute; minutes > 0 || s == "" {
+		s += strconv.Itoa(int(minutes)) + "m"
+	}
+	return s
+}
+
+// ListTimezones lists the embedded IANA zones with their offsets at a given instant
+func ListTimezones(req timestampmodels.TimezonesRequest) (timestampmodels.TimezonesResponse, error) {
+	at, err := parseReferenceTime(req.At, time.UTC)
+	if err != nil {
+		return timestampmodels.TimezonesResponse{}, errors.New("invalid at: " + strings.TrimPrefix(err.Error(), "invalid reference time: "))
+	}
+	query := strings.ToLower(strings.TrimSpace(req.Query))
+
+	zones := []timestampmodels.TimezoneInfo{}
+	for _, name := range timezoneNames {
+		if query != "" && !strings.Contains(strings.ToLower(name), query) {
+			continue
+		}
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			continue
+		}
+
+		local := at.In(loc)
+		abbreviation, offset := local.Zone()
+		info := timestampmodels.TimezoneInfo{
+			Timezone:      name,
+			LocalTime:     local.Format(time.RFC3339),
+			Offset:        local.Format("-07:00"),
+			OffsetSeconds: offset,
+			Abbreviation:  abbreviation,
+			DST:           local.IsDST(),
+		}
+		if transitions := zoneTransitions(at, at.AddDate(1, 0, 0), loc); len(transitions) > 0 {
+			info.NextTransition = transitions[0].Format(time.RFC3339)
+		}
+		zones = append(zones, info)
+	}
+
+	return timestampmodels.TimezonesResponse{
+		At:        at.UTC().Format(time.RFC3339),
+		Count:     len(zones),
+		Timezones: zones,
+	}, nil
+}
+
+// TimezoneTransitions lists the offset changes of a zone within a date range
+func TimezoneTransitions(req timestampmodels.TimezoneTransitionsRequest) (timestampmodels.TimezoneTransitionsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return timestampmodels.TimezoneTransitionsResponse{}, err
+	}
+
+	loc, err := time.LoadLocation(strings.TrimSpace(req.Timezone))
+	if err != nil {
+		return timestampmodels.TimezoneTransitionsResponse{}, errors.New("invalid timezone: " + err.Error())
+	}
+
+	start, err := parseReferenceTime(req.Start, loc)
+	if err != nil {
+		return timestampmodels.TimezoneTransitionsResponse{}, errors.New("invalid start: " + strings.TrimPrefix(err.Error(), "invalid reference time: "))
+	}
+	end := start.AddDate(1, 0, 0)
+	if req.End != "" {
+		if end, err = parseReferenceTime(req.End, loc); err != nil {
+			return timestampmodels.TimezoneTransitionsResponse{}, errors.New("invalid end: " + strings.TrimPrefix(err.Error(), "invalid reference time: "))
+		}
+	}
+	if end.Before(start) {
+		return timestampmodels.TimezoneTransitionsResponse{}, errors.New("end must not be before start")
+	}
+	if end.After(start.AddDate(timestampmodels.MaxTransitionRangeYears, 0, 0)) {
+		return timestampmodels.TimezoneTransitionsResponse{}, errors.New("range must not exceed " + strconv.Itoa(timestampmodels.MaxTransitionRangeYears) + " years")
+	}
+
+	transitions := []timestampmodels.TimezoneTransition{}
+	for _, at := range zoneTransitions(start, end, loc) {
+		before := at.Add(-time.Second).In(loc)
+		after := at.In(loc)
+		abbreviationBefore, _ := before.Zone()
+		abbreviationAfter, _ := after.Zone()
+		transitions = append(transitions, timestampmodels.TimezoneTransition{
+			Time:               after.Format(time.RFC3339),
+			GMT:                at.UTC().Format(time.RFC3339),
+			Seconds:            at.Unix(),
+			OffsetBefore:       before.Format("-07:00"),
+			OffsetAfter:        after.Format("-07:00"),
+			AbbreviationBefore: abbreviationBefore,
+			AbbreviationAfter:  abbreviationAfter,
+			DST:                after.IsDST(),
+			Description:        describeTransition(at, loc),
+		})
+	}
+
+	return timestampmodels.TimezoneTransitionsResponse{
+		Timezone:    loc.String(),
+		Start:       start.In(loc).Format(time.RFC3339),
+		End:         end.In(loc).Format(time.RFC3339),
+		Transitions: transitions,
+	}, nil
+}
@@ -2,6 +2,9 @@ package usecase
 
 import (
 	"errors"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
 	timestampmodels "konverter/internal/timestamp/models"
@@ -15,25 +18,54 @@ func ConvertHumanize(req timestampmodels.ConvertHumanizeRequest) (timestampmodel
 		return timestampmodels.ConvertHumanizeResponse{}, err
 	}
 
-	// Detect the unit of the input timestamp
-	detectedUnit := detectUnit(req.Timestamp)
+	timestamp := *req.Timestamp
 
-	// Normalize to seconds for time calculations
-	seconds := normalizeToSeconds(req.Timestamp, detectedUnit)
+	// Use the explicit unit if given, otherwise detect it from the magnitude
+	detectedUnit := timestampmodels.Units[req.Unit]
+	if detectedUnit == "" {
+		detectedUnit = DetectUnit(timestamp)
+	}
+
+	// Create time object keeping the sub-second part
+	t, err := timeFromUnit(timestamp, detectedUnit)
+	if err != nil {
+		return timestampmodels.ConvertHumanizeResponse{}, err
+	}
 
-	// Create time object from seconds
-	t := time.Unix(seconds, 0)
+	// Calendar output uses the timezone, UTC by default
+	loc := time.UTC
+	if req.Timezone != "" {
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return timestampmodels.ConvertHumanizeResponse{}, errors.New("invalid timezone: " + err.Error())
+		}
+	}
 
-	// Build response
+	// Describe the time relative to the reference, using go-humanize's wording for English
+	reference, err := parseReferenceTime(req.Reference, loc)
+	if err != nil {
+		return timestampmodels.ConvertHumanizeResponse{}, err
+	}
+	lang := resolveLanguage(req.Locale)
+	precise, relative := lang.relativeTime(t, reference, loc)
+	if lang == languages["en"] {
+		relative = humanize.RelTime(t, reference, "ago", "from now")
+	}
+
+	// Build response; finer units are omitted when they overflow int64
 	responseHumanize := timestampmodels.ConvertHumanizeResponse{
-		InputTimestamp: req.Timestamp,
-		DetectedUnit:   detectedUnit,
-		Seconds:        seconds,
-		Milliseconds:   normalizeToMilliseconds(req.Timestamp, detectedUnit),
-		Microseconds:   normalizeToMicroseconds(req.Timestamp, detectedUnit),
-		Nanoseconds:    normalizeToNanoseconds(req.Timestamp, detectedUnit),
-		GMT:            t.UTC().Format(time.RFC3339),
-		Relative:       humanize.Time(t),
+		InputTimestamp:  timestamp,
+		DetectedUnit:    detectedUnit,
+		Seconds:         t.Unix(),
+		Milliseconds:    unixInPtr(t, 1000),
+		Microseconds:    unixInPtr(t, 1000000),
+		Nanoseconds:     unixInPtr(t, 1000000000),
+		GMT:             t.UTC().Format(time.RFC3339Nano),
+		Relative:        relative,
+		RelativePrecise: precise,
+		Sentinels:       findSentinels(timestamp, detectedUnit),
+	}
+	if req.Reference != "" {
+		responseHumanize.ReferenceTime = reference.In(loc).Format(time.RFC3339Nano)
 	}
 
 	// Handle timezone-specific time if provided
@@ -45,6 +77,15 @@ func ConvertHumanize(req timestampmodels.ConvertHumanizeRequest) (timestampmodel
 		responseHumanize.TimezoneTime = timezoneTime
 	}
 
+	// Render requested output formats in the timezone, UTC by default
+	if responseHumanize.Formatted, err = formatOutputs(t.In(loc), req.OutputFormats, req.LayoutSyntax, lang); err != nil {
+		return timestampmodels.ConvertHumanizeResponse{}, err
+	}
+
+	if responseHumanize.WorldClock, err = worldClock(t, req.Timezones, req.BusinessHoursStart, req.BusinessHoursEnd); err != nil {
+		return timestampmodels.ConvertHumanizeResponse{}, err
+	}
+
 	return responseHumanize, nil
 }
 
@@ -54,24 +95,108 @@ func ConvertDateToUnix(req timestampmodels.DateToUnixRequest) (timestampmodels.D
 		return timestampmodels.DateToUnixResponse{}, err
 	}
 
-	// Parse the date string using multiple format attempts
-	parsedTime, detectedFormat, err := tryParseDateFormats(req.DateString)
+	// Zone-less date strings are interpreted in the requested timezone, UTC by default
+	loc := time.UTC
+	if req.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return timestampmodels.DateToUnixResponse{}, errors.New("invalid timezone: " + err.Error())
+		}
+	}
+
+	// Parse the date string with the caller's layout, or using multiple format attempts
+	var matches []parsedDate
+	var err error
+	if req.Layout != "" {
+		t, format, layoutErr := parseWithLayout(req.DateString, req.Layout, req.LayoutSyntax, loc)
+		matches, err = []parsedDate{{time: t, format: format}}, layoutErr
+	} else {
+		matches, err = tryParseDateFormats(req.DateString, loc)
+	}
+
+	// Fall back to ISO week and ordinal dates and relative expressions
+	var reference time.Time
+	if err != nil && req.Layout == "" {
+		if reference, err = parseReferenceTime(req.Reference, loc); err != nil {
+			return timestampmodels.DateToUnixResponse{}, err
+		}
+		var natural parsedDate
+		natural, err = parseNaturalDate(req.DateString, reference, loc)
+		matches = []parsedDate{natural}
+	}
 	if err != nil {
 		return timestampmodels.DateToUnixResponse{}, err
 	}
 
-	// Convert to Unix timestamp in seconds
-	unixSeconds := parsedTime.Unix()
+	// Pick the reading in the preferred date order; without one, the first format in preference order wins
+	order, err := resolveDateOrder(req.DateOrder, req.Locale)
+	if err != nil {
+		return timestampmodels.DateToUnixResponse{}, err
+	}
+	chosen := 0
+	if len(matches) > 1 {
+		chosen = slices.IndexFunc(matches, func(m parsedDate) bool { return m.format.order == order })
+		if chosen < 0 {
+			if req.Strict {
+				return timestampmodels.DateToUnixResponse{}, ambiguousDateError(matches, "date_order or locale")
+			}
+			chosen = 0
+		}
+	}
+	parsedTime, detectedFormat := matches[chosen].time, matches[chosen].format
+
+	// Place zone-less wall clocks in the requested timezone, reporting times DST skipped or repeated
+	var localTimeStatus string
+	var candidates []time.Time
+	if !detectedFormat.zoned {
+		parsedTime, localTimeStatus, candidates = LocalizeWallClock(parsedTime, loc)
+		if req.Timezone == "" {
+			localTimeStatus = ""
+		}
+	}
 
-	// Build response
+	// Build response, keeping any fractional seconds from the input
 	response := timestampmodels.DateToUnixResponse{
 		InputDateString: req.DateString,
-		DetectedFormat:  detectedFormat,
-		Seconds:         unixSeconds,
-		Milliseconds:    unixSeconds * 1000,
-		Microseconds:    unixSeconds * 1000000,
-		Nanoseconds:     unixSeconds * 1000000000,
-		GMT:             parsedTime.UTC().Format(time.RFC3339),
+		DetectedFormat:  detectedFormat.name,
+		Layout:          detectedFormat.layout,
+		Seconds:         parsedTime.Unix(),
+		Milliseconds:    unixInPtr(parsedTime, 1000),
+		Microseconds:    unixInPtr(parsedTime, 1000000),
+		Nanoseconds:     unixInPtr(parsedTime, 1000000000),
+		GMT:             parsedTime.UTC().Format(time.RFC3339Nano),
+		LocalTimeStatus: localTimeStatus,
+	}
+	if detectedFormat.name == "relative" {
+		response.ReferenceTime = reference.In(loc).Format(time.RFC3339Nano)
+	}
+	for _, candidate := range candidates {
+		abbreviation, _ := candidate.Zone()
+		response.Candidates = append(response.Candidates, timestampmodels.LocalTimeCandidate{
+			Seconds:      candidate.Unix(),
+			GMT:          candidate.UTC().Format(time.RFC3339Nano),
+			TimezoneTime: candidate.Format(time.RFC3339Nano),
+			Offset:       candidate.Format("-07:00"),
+			Abbreviation: abbreviation,
+		})
+	}
+
+	// Report every reading of an ambiguous date string
+	if len(matches) > 1 {
+		response.Ambiguous = true
+		for i, m := range matches {
+			instant := m.time
+			if !m.format.zoned {
+				instant, _, _ = LocalizeWallClock(m.time, loc)
+			}
+			response.Interpretations = append(response.Interpretations, timestampmodels.DateInterpretation{
+				Format:  m.format.name,
+				Date:    m.time.Format("2006-01-02T15:04:05.999999999"),
+				Seconds: instant.Unix(),
+				GMT:     instant.UTC().Format(time.RFC3339Nano),
+				Chosen:  i == chosen,
+			})
+		}
 	}
 
 	// Handle timezone-specific time if provided
@@ -83,5 +208,241 @@ func ConvertDateToUnix(req timestampmodels.DateToUnixRequest) (timestampmodels.D
 		response.TimezoneTime = timezoneTime
 	}
 
+	if response.Formatted, err = formatOutputs(parsedTime.In(loc), req.OutputFormats, req.LayoutSyntax, resolveLanguage(req.Locale)); err != nil {
+		return timestampmodels.DateToUnixResponse{}, err
+	}
+
+	if response.WorldClock, err = worldClock(parsedTime, req.Timezones, req.BusinessHoursStart, req.BusinessHoursEnd); err != nil {
+		return timestampmodels.DateToUnixResponse{}, err
+	}
+
+	return response, nil
+}
+
+// ConvertEpoch converts a time in an alternative epoch format and returns it in every supported format
+func ConvertEpoch(req timestampmodels.ConvertEpochRequest) (timestampmodels.ConvertEpochResponse, error) {
+	if err := req.Validate(); err != nil {
+		return timestampmodels.ConvertEpochResponse{}, err
+	}
+
+	name, format, ok := resolveEpochFormat(req.Format)
+	if !ok {
+		return timestampmodels.ConvertEpochResponse{}, errors.New("unsupported format: " + req.Format + ", expected one of " + strings.Join(epochFormatNames, ", "))
+	}
+
+	t, err := format.parse(strings.TrimSpace(req.Value))
+	if err != nil {
+		return timestampmodels.ConvertEpochResponse{}, errors.New("invalid " + name + " value: " + err.Error())
+	}
+
+	// Build response with every format that can represent the instant
+	response := timestampmodels.ConvertEpochResponse{
+		Format:          name,
+		InputValue:      req.Value,
+		Seconds:         t.Unix(),
+		Nanoseconds:     unixInPtr(t, 1000000000),
+		GMT:             t.UTC().Format(time.RFC3339Nano),
+		Relative:        humanize.Time(t),
+		Representations: map[string]string{},
+	}
+	for _, formatName := range epochFormatNames {
+		if value, ok := epochFormats[formatName].format(t); ok {
+			response.Representations[formatName] = value
+		}
+	}
+
+	// Handle timezone-specific time if provided
+	if req.Timezone != "" {
+		timezoneTime, err := formatTimezoneTime(t, req.Timezone)
+		if err != nil {
+			return timestampmodels.ConvertEpochResponse{}, errors.New("invalid timezone: " + err.Error())
+		}
+		response.TimezoneTime = timezoneTime
+	}
+
+	return response, nil
+}
+
+// DecodeID extracts the embedded timestamp and other fields from a time-based identifier
+func DecodeID(req timestampmodels.DecodeIDRequest) (timestampmodels.DecodeIDResponse, error) {
+	if err := req.Validate(); err != nil {
+		return timestampmodels.DecodeIDResponse{}, err
+	}
+
+	id := strings.TrimSpace(req.ID)
+	idType := req.Type
+	if idType == "" {
+		var err error
+		if idType, err = detectIDType(id); err != nil {
+			return timestampmodels.DecodeIDResponse{}, err
+		}
+	}
+
+	var decoded decodedID
+	var err error
+	switch idType {
+	case "ulid":
+		decoded, err = decodeULID(id)
+	case "uuid":
+		decoded, err = decodeUUID(id)
+	case "snowflake":
+		decoded, err = decodeSnowflake(id, req.SnowflakePreset, req.SnowflakeEpoch)
+	case "ksuid":
+		decoded, err = decodeKSUID(id)
+	case "objectid":
+		decoded, err = decodeObjectID(id)
+	}
+	if err != nil {
+		return timestampmodels.DecodeIDResponse{}, err
+	}
+
+	t := decoded.time
+	response := timestampmodels.DecodeIDResponse{
+		ID:     id,
+		IDType: decoded.idType,
+		Fields: decoded.fields,
+		ConvertHumanizeResponse: timestampmodels.ConvertHumanizeResponse{
+			InputTimestamp: decoded.timestamp,
+			DetectedUnit:   decoded.unit,
+			Seconds:        t.Unix(),
+			Milliseconds:   unixInPtr(t, 1000),
+			Microseconds:   unixInPtr(t, 1000000),
+			Nanoseconds:    unixInPtr(t, 1000000000),
+			GMT:            t.UTC().Format(time.RFC3339Nano),
+			Relative:       humanize.Time(t),
+		},
+	}
+
+	// Handle timezone-specific time if provided
+	if req.Timezone != "" {
+		timezoneTime, err := formatTimezoneTime(t, req.Timezone)
+		if err != nil {
+			return timestampmodels.DecodeIDResponse{}, errors.New("invalid timezone: " + err.Error())
+		}
+		response.TimezoneTime = timezoneTime
+	}
+
+	return response, nil
+}
+
+// ParseDuration parses a duration and returns it in every representation
+func ParseDuration(req timestampmodels.DurationRequest) (timestampmodels.DurationResponse, error) {
+	if err := req.Validate(); err != nil {
+		return timestampmodels.DurationResponse{}, err
+	}
+
+	d, format, err := parseDuration(req.Duration, req.Unit)
+	if err != nil {
+		return timestampmodels.DurationResponse{}, err
+	}
+
+	response := timestampmodels.DurationResponse{
+		Input:       req.Duration,
+		Format:      format,
+		Components:  durationComponents(d),
+		ISO8601:     formatISODuration(d),
+		Humanized:   humanizeDuration(d),
+		Totals:      nominalTotals(d),
+		Approximate: d.months != 0 || d.days != 0,
+	}
+	if d.months == 0 && d.days == 0 {
+		response.GoDuration = d.clock.String()
+	}
+
+	return response, nil
+}
+
+// AddDuration adds or subtracts a duration to a date with calendar-aware month and day handling
+func AddDuration(req timestampmodels.DurationAddRequest) (timestampmodels.DurationAddResponse, error) {
+	if err := req.Validate(); err != nil {
+		return timestampmodels.DurationAddResponse{}, err
+	}
+
+	loc := time.UTC
+	if req.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return timestampmodels.DurationAddResponse{}, errors.New("invalid timezone: " + err.Error())
+		}
+	}
+
+	start, err := resolveDate(req.Date, req.DateOrder, loc)
+	if err != nil {
+		return timestampmodels.DurationAddResponse{}, err
+	}
+	d, _, err := parseDuration(req.Duration, req.Unit)
+	if err != nil {
+		return timestampmodels.DurationAddResponse{}, err
+	}
+	if req.Subtract {
+		d = d.negate()
+	}
+
+	result := addCalendar(start, d, loc)
+	response := timestampmodels.DurationAddResponse{
+		Start:        start.In(loc).Format(time.RFC3339Nano),
+		Duration:     formatISODuration(d),
+		Seconds:      result.Unix(),
+		Milliseconds: unixInPtr(result, 1000),
+		GMT:          result.UTC().Format(time.RFC3339Nano),
+	}
+	if elapsed := result.Sub(start); elapsed != math.MaxInt64 && elapsed != math.MinInt64 {
+		response.Elapsed = elapsed.String()
+	}
+	if req.Timezone != "" {
+		response.TimezoneTime = result.In(loc).Format(time.RFC3339Nano)
+	}
+
+	return response, nil
+}
+
+// DurationBetween computes the calendar and exact difference between two dates
+func DurationBetween(req timestampmodels.DurationBetweenRequest) (timestampmodels.DurationBetweenResponse, error) {
+	if err := req.Validate(); err != nil {
+		return timestampmodels.DurationBetweenResponse{}, err
+	}
+
+	loc := time.UTC
+	if req.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return timestampmodels.DurationBetweenResponse{}, errors.New("invalid timezone: " + err.Error())
+		}
+	}
+
+	start, err := resolveDate(req.Start, req.DateOrder, loc)
+	if err != nil {
+		return timestampmodels.DurationBetweenResponse{}, errors.New("invalid start: " + err.Error())
+	}
+	end, err := resolveDate(req.End, req.DateOrder, loc)
+	if err != nil {
+		return timestampmodels.DurationBetweenResponse{}, errors.New("invalid end: " + err.Error())
+	}
+
+	// Measure forward from the earlier date so months and days are counted on the calendar
+	negative := end.Before(start)
+	from, to := start, end
+	if negative {
+		from, to = end, start
+	}
+	d := calendarDiff(from, to, loc)
+	signed := d
+	if negative {
+		signed = d.negate()
+	}
+
+	response := timestampmodels.DurationBetweenResponse{
+		Start:      start.In(loc).Format(time.RFC3339Nano),
+		End:        end.In(loc).Format(time.RFC3339Nano),
+		Negative:   negative,
+		Components: durationComponents(d),
+		ISO8601:    formatISODuration(signed),
+		Humanized:  humanizeDuration(d),
+		Totals:     elapsedTotals(start, end),
+	}
+	if elapsed := end.Sub(start); elapsed != math.MaxInt64 && elapsed != math.MinInt64 {
+		response.Elapsed = elapsed.String()
+	}
+
 	return response, nil
 }
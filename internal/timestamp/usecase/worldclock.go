@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	timestampmodels "konverter/internal/timestamp/models"
+)
+
+// timezonePresetEnvPrefix prefixes environment variables that define named zone lists,
+// e.g. TIMEZONE_PRESET_TEAM="America/New_York,Europe/London,Asia/Ho_Chi_Minh,Asia/Tokyo"
+const timezonePresetEnvPrefix = "TIMEZONE_PRESET_"
+
+// Default business hours, local time on weekdays
+const (
+	defaultBusinessHoursStart = "09:00"
+	defaultBusinessHoursEnd   = "17:00"
+)
+
+// expandTimezones resolves IANA zone names and preset names to a de-duplicated list of zone names
+// Empty entries are skipped; "Local" is rejected, since it names the server's zone rather than one the caller chose
+func expandTimezones(entries []string) ([]string, error) {
+	zones := []string{}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		names := []string{entry}
+		if preset := os.Getenv(timezonePresetEnvPrefix + strings.ToUpper(entry)); preset != "" && !strings.Contains(entry, "/") {
+			names = strings.Split(preset, ",")
+		}
+
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if name == "Local" {
+				return nil, errors.New("unknown timezone or preset: Local, use an IANA zone name")
+			}
+			if _, err := time.LoadLocation(name); err != nil {
+				return nil, errors.New("unknown timezone or preset: " + name)
+			}
+			if !slices.Contains(zones, name) {
+				zones = append(zones, name)
+			}
+		}
+	}
+	if len(zones) == 0 {
+		return nil, errors.New("no timezones given")
+	}
+	return zones, nil
+}
+
+// parseBusinessHours parses "HH:MM" start and end times of day, using the defaults when empty
+func parseBusinessHours(start, end string) (time.Duration, time.Duration, error) {
+	if start == "" {
+		start = defaultBusinessHoursStart
+	}
+	if end == "" {
+		end = defaultBusinessHoursEnd
+	}
+	startTime, err := time.Parse("15:04", start)
+	if err != nil {
+		return 0, 0, errors.New("business_hours_start must be HH:MM")
+	}
+	endTime, err := time.Parse("15:04", end)
+	if err != nil {
+		return 0, 0, errors.New("business_hours_end must be HH:MM")
+	}
+	return startTime.Sub(startTime.Truncate(24 * time.Hour)), endTime.Sub(endTime.Truncate(24 * time.Hour)), nil
+}
+
+// worldClock shows t in every zone, flagging local times within business hours on weekdays
+// An end before start means the hours span midnight
+func worldClock(t time.Time, entries []string, businessStart, businessEnd string) ([]timestampmodels.ZoneTime, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	zones, err := expandTimezones(entries)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseBusinessHours(businessStart, businessEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	clock := make([]timestampmodels.ZoneTime, 0, len(zones))
+	for _, zone := range zones {
+		loc, _ := time.LoadLocation(zone)
+		local := t.In(loc)
+		abbreviation, _ := local.Zone()
+		sinceMidnight := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second
+
+		inHours := sinceMidnight >= start && sinceMidnight < end
+		if end <= start {
+			inHours = sinceMidnight >= start || sinceMidnight < end
+		}
+		weekday := local.Weekday()
+
+		clock = append(clock, timestampmodels.ZoneTime{
+			Timezone:      zone,
+			LocalTime:     local.Format(time.RFC3339Nano),
+			Weekday:       weekday.String(),
+			Offset:        local.Format("-07:00"),
+			Abbreviation:  abbreviation,
+			DST:           local.IsDST(),
+			BusinessHours: inHours && weekday != time.Saturday && weekday != time.Sunday,
+		})
+	}
+	return clock, nil
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	// Embed the IANA tz database so named timezones load without zoneinfo in the container image
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 )